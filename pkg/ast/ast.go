@@ -46,8 +46,10 @@ func (p *Program) String() string {
 }
 
 // LET STATEMENT -> "let <identifier> = <expression>;"
+// CONST STATEMENT -> "const <identifier> = <expression>;"
+// RE-ASSIGNMENT STATEMENT -> "<identifier> = <expression>;"
 type AssignmentStatement struct {
-	Token              token.Token // token.LET
+	Token              token.Token // token.LET, token.CONST or token.IDENTIF(re-assignment)
 	AssignmentOperator token.Token
 	Name               *Identifier
 	Value              Expression
@@ -58,7 +60,9 @@ func (aStatement *AssignmentStatement) TokenLiteral() string { return aStatement
 func (aStatement *AssignmentStatement) String() string {
 	var out bytes.Buffer
 
-	out.WriteString(aStatement.TokenLiteral() + " ")
+	if aStatement.Token.Type != token.IDENTIF {
+		out.WriteString(aStatement.TokenLiteral() + " ")
+	}
 	out.WriteString(aStatement.Name.String())
 	out.WriteString(aStatement.AssignmentOperator.Literal)
 
@@ -74,13 +78,19 @@ func (aStatement *AssignmentStatement) String() string {
 /* EXPRESSIONS */
 
 type Identifier struct {
-	Token token.Token // token.IDENT
-	Value string
+	Token      token.Token // token.IDENT
+	Value      string
+	Annotation TypeAnnotation // Optional, only on declared identifiers(let, const and parameters)
 }
 
 func (ident *Identifier) expressionNode()      {}
 func (ident *Identifier) TokenLiteral() string { return ident.Token.Literal }
-func (ident *Identifier) String() string       { return ident.Value }
+func (ident *Identifier) String() string {
+	if ident.Annotation != nil {
+		return ident.Value + ": " + ident.Annotation.String()
+	}
+	return ident.Value
+}
 
 type IntegerLiteral struct {
 	Token token.Token // token.INT
@@ -109,9 +119,31 @@ func (sl *StringLiteral) expressionNode()      {}
 func (sl *StringLiteral) TokenLiteral() string { return sl.Token.Literal }
 func (sl *StringLiteral) String() string       { return sl.Token.Literal }
 
+// TEMPLATE LITERAL -> "<text>${<expression>}<text>"
+type TemplateLiteral struct {
+	Token token.Token  // Token.TEMPLATE
+	Parts []Expression // StringLiterals for text between the interpolated expressions
+}
+
+func (tl *TemplateLiteral) expressionNode()      {}
+func (tl *TemplateLiteral) TokenLiteral() string { return tl.Token.Literal }
+func (tl *TemplateLiteral) String() string {
+	var out bytes.Buffer
+	for _, part := range tl.Parts {
+		if text, ok := part.(*StringLiteral); ok {
+			out.WriteString(text.Value)
+		} else {
+			out.WriteString("${" + part.String() + "}")
+		}
+	}
+	return out.String()
+}
+
 type FunctionLiteral struct {
 	Token      token.Token // token.FUNCTION
+	Name       string      // Empty for anonymous functions
 	Parameters []*Identifier
+	ReturnType TypeAnnotation // Optional
 	Body       *BlockStatement
 }
 
@@ -124,9 +156,15 @@ func (fl *FunctionLiteral) String() string {
 		params = append(params, p.String())
 	}
 	out.WriteString(fl.TokenLiteral())
+	if fl.Name != "" {
+		out.WriteString(" " + fl.Name)
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(") ")
+	if fl.ReturnType != nil {
+		out.WriteString("-> " + fl.ReturnType.String() + " ")
+	}
 	out.WriteString(fl.Body.String())
 	return out.String()
 }
@@ -150,9 +188,29 @@ func (al *ArrayLiteral) String() string {
 	return out.String()
 }
 
+type SetLiteral struct {
+	Token    token.Token // token.SET_LBRACE
+	Elements []Expression
+}
+
+func (sl *SetLiteral) expressionNode()      {}
+func (sl *SetLiteral) TokenLiteral() string { return sl.Token.Literal }
+func (sl *SetLiteral) String() string {
+	var out bytes.Buffer
+	elements := []string{}
+	for _, el := range sl.Elements {
+		elements = append(elements, el.String())
+	}
+	out.WriteString("#{")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("}")
+	return out.String()
+}
+
 type HashLiteral struct {
 	Token token.Token // token.LBRACE
 	Pairs map[Expression]Expression
+	Keys  []Expression // Keys of Pairs in order of appearance
 }
 
 func (hl *HashLiteral) expressionNode()      {}
@@ -160,8 +218,8 @@ func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
 func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 	pairsMsg := []string{}
-	for key, value := range hl.Pairs {
-		pairsMsg = append(pairsMsg, key.String()+" : "+value.String())
+	for _, key := range hl.Keys {
+		pairsMsg = append(pairsMsg, key.String()+" : "+hl.Pairs[key].String())
 	}
 	out.WriteString("{")
 	out.WriteString(strings.Join(pairsMsg, ", "))
@@ -208,6 +266,25 @@ func (inexp *InfixExpression) String() string {
 	return out.String()
 }
 
+// PIPE EXPRESSION -> <left expression> |> <call expression>
+type PipeExpression struct {
+	Token token.Token // token.PIPE
+	Left  Expression
+	Right Expression // CallExpression the left value is prepended to OR function
+}
+
+func (pe *PipeExpression) expressionNode()      {}
+func (pe *PipeExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PipeExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(pe.Left.String())
+	out.WriteString(" |> ")
+	out.WriteString(pe.Right.String())
+	out.WriteString(")")
+	return out.String()
+}
+
 // IF EXPRESSION -> "if (<condition>) <consequence> else <alternative>"
 type IfExpression struct {
 	Token       token.Token // token.IF
@@ -232,6 +309,32 @@ func (ifexp *IfExpression) String() string {
 	return out.String()
 }
 
+// TRY EXPRESSION -> "try <block> catch (<identifier>) <block> finally <block>", catch or finally can be omitted
+type TryExpression struct {
+	Token   token.Token // token.TRY
+	Block   *BlockStatement
+	Param   *Identifier // Bound to the caught error, nil without catch
+	Catch   *BlockStatement
+	Finally *BlockStatement
+}
+
+func (te *TryExpression) expressionNode()      {}
+func (te *TryExpression) TokenLiteral() string { return te.Token.Literal }
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("try ")
+	out.WriteString(te.Block.String())
+	if te.Catch != nil {
+		out.WriteString(" catch(" + te.Param.String() + ") ")
+		out.WriteString(te.Catch.String())
+	}
+	if te.Finally != nil {
+		out.WriteString(" finally ")
+		out.WriteString(te.Finally.String())
+	}
+	return out.String()
+}
+
 // WHILE EXPRESSION -> "while (<condition>) <consequence> "
 type WhileExpression struct {
 	Token     token.Token // token.WHILE
@@ -292,10 +395,36 @@ func (ie *IndexExpression) String() string {
 	return out.String()
 }
 
+// SLICE EXPRESSION -> <expression>[<expression>:<expression>] (start and end optional)
+type SliceExpression struct {
+	Token token.Token // token.LBRACKET
+	Left  Expression
+	Start Expression
+	End   Expression
+}
+
+func (se *SliceExpression) expressionNode()      {}
+func (se *SliceExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SliceExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("(")
+	out.WriteString(se.Left.String())
+	out.WriteString("[")
+	if se.Start != nil {
+		out.WriteString(se.Start.String())
+	}
+	out.WriteString(":")
+	if se.End != nil {
+		out.WriteString(se.End.String())
+	}
+	out.WriteString("])")
+	return out.String()
+}
+
 // DOT EXPRESSION -> <expression>.<expression>
 
 type DotExpression struct {
-	Token     token.Token // token.DOT
+	Token     token.Token // token.DOT, or token.OPTIONAL_DOT which is null when Left is null
 	Left      Expression
 	Attribute Expression
 }
@@ -306,7 +435,7 @@ func (de *DotExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString("(")
 	out.WriteString(de.Left.String())
-	out.WriteString(".")
+	out.WriteString(de.TokenLiteral())
 	out.WriteString(de.Attribute.String())
 	out.WriteString(")")
 	return out.String()
@@ -314,6 +443,81 @@ func (de *DotExpression) String() string {
 
 /* STATEMENTS */
 
+// FUNCTION DECLARATION -> "fun <identifier>(<comma seperated identifiers>) <body>"
+type FunctionDeclaration struct {
+	Token    token.Token // token.FUNCTION
+	Name     *Identifier
+	Function *FunctionLiteral
+}
+
+func (fd *FunctionDeclaration) statementNode()       {}
+func (fd *FunctionDeclaration) TokenLiteral() string { return fd.Token.Literal }
+func (fd *FunctionDeclaration) String() string       { return fd.Function.String() }
+
+// STRUCT DEFINITION -> "struct <identifier> { <comma seperated identifiers> }"
+type StructDefinition struct {
+	Token  token.Token // token.STRUCT
+	Name   *Identifier
+	Fields []*Identifier
+}
+
+func (sd *StructDefinition) statementNode()       {}
+func (sd *StructDefinition) TokenLiteral() string { return sd.Token.Literal }
+func (sd *StructDefinition) String() string {
+	fields := []string{}
+	for _, f := range sd.Fields {
+		fields = append(fields, f.String())
+	}
+	return sd.TokenLiteral() + " " + sd.Name.String() + " { " + strings.Join(fields, ", ") + " }"
+}
+
+// TYPE DEFINITION -> "type <identifier> = <variant> | <variant> ..."
+type TypeDefinition struct {
+	Token    token.Token // Identifier "type"
+	Name     *Identifier
+	Variants []*Variant
+}
+
+func (td *TypeDefinition) statementNode()       {}
+func (td *TypeDefinition) TokenLiteral() string { return td.Token.Literal }
+func (td *TypeDefinition) String() string {
+	variants := []string{}
+	for _, v := range td.Variants {
+		variants = append(variants, v.String())
+	}
+	return td.TokenLiteral() + " " + td.Name.String() + " = " + strings.Join(variants, " | ")
+}
+
+// VARIANT -> "<identifier>" | "<identifier>(<comma seperated identifiers>)"
+type Variant struct {
+	Name   *Identifier
+	Fields []*Identifier
+}
+
+func (v *Variant) String() string {
+	if len(v.Fields) == 0 {
+		return v.Name.String()
+	}
+	fields := []string{}
+	for _, f := range v.Fields {
+		fields = append(fields, f.String())
+	}
+	return v.Name.String() + "(" + strings.Join(fields, ", ") + ")"
+}
+
+// FIELD ASSIGNMENT STATEMENT -> "<expression>.<identifier> = <expression>;"
+type FieldAssignmentStatement struct {
+	Token  token.Token // Assignment operator(=, =& or =*)
+	Target *DotExpression
+	Value  Expression
+}
+
+func (fas *FieldAssignmentStatement) statementNode()       {}
+func (fas *FieldAssignmentStatement) TokenLiteral() string { return fas.Token.Literal }
+func (fas *FieldAssignmentStatement) String() string {
+	return fas.Target.String() + fas.Token.Literal + fas.Value.String() + ";"
+}
+
 // RETURN STATEMENT -> "return <expression>;"
 type ReturnStatement struct {
 	Token       token.Token // token.RETURN
@@ -332,6 +536,18 @@ func (rS *ReturnStatement) String() string {
 	return out.String()
 }
 
+// THROW STATEMENT -> "throw <expression>;"
+type ThrowStatement struct {
+	Token token.Token // token.THROW
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode()       {}
+func (ts *ThrowStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *ThrowStatement) String() string {
+	return ts.TokenLiteral() + " " + ts.Value.String() + ";"
+}
+
 // EXPRESSION STATEMENT -> "<expression>;"
 type ExpressionStatement struct {
 	Token      token.Token
@@ -362,3 +578,71 @@ func (bs *BlockStatement) String() string {
 	}
 	return out.String()
 }
+
+/* TYPE ANNOTATIONS */
+
+// Optional static types, ignored by the evaluator and checked by the typecheck package
+type TypeAnnotation interface {
+	Node
+	typeNode()
+}
+
+// NAMED TYPE -> int | bool | string | null | any
+type NamedType struct {
+	Token token.Token // token.IDENTIF
+	Name  string
+}
+
+func (nt *NamedType) typeNode()            {}
+func (nt *NamedType) TokenLiteral() string { return nt.Token.Literal }
+func (nt *NamedType) String() string       { return nt.Name }
+
+// ARRAY TYPE -> [<type>]
+type ArrayType struct {
+	Token   token.Token // token.LBRACKET
+	Element TypeAnnotation
+}
+
+func (at *ArrayType) typeNode()            {}
+func (at *ArrayType) TokenLiteral() string { return at.Token.Literal }
+func (at *ArrayType) String() string       { return "[" + at.Element.String() + "]" }
+
+// HASH TYPE -> {<type>: <type>}
+type HashType struct {
+	Token token.Token // token.LBRACE
+	Key   TypeAnnotation
+	Value TypeAnnotation
+}
+
+func (ht *HashType) typeNode()            {}
+func (ht *HashType) TokenLiteral() string { return ht.Token.Literal }
+func (ht *HashType) String() string {
+	return "{" + ht.Key.String() + ": " + ht.Value.String() + "}"
+}
+
+// SET TYPE -> #{<type>}
+type SetType struct {
+	Token   token.Token // token.SET_LBRACE
+	Element TypeAnnotation
+}
+
+func (st *SetType) typeNode()            {}
+func (st *SetType) TokenLiteral() string { return st.Token.Literal }
+func (st *SetType) String() string       { return "#{" + st.Element.String() + "}" }
+
+// FUNCTION TYPE -> fun(<comma seperated types>) -> <type>
+type FunctionType struct {
+	Token      token.Token // token.FUNCTION
+	Parameters []TypeAnnotation
+	Return     TypeAnnotation
+}
+
+func (ft *FunctionType) typeNode()            {}
+func (ft *FunctionType) TokenLiteral() string { return ft.Token.Literal }
+func (ft *FunctionType) String() string {
+	params := []string{}
+	for _, p := range ft.Parameters {
+		params = append(params, p.String())
+	}
+	return "fun(" + strings.Join(params, ", ") + ") -> " + ft.Return.String()
+}
@@ -2,9 +2,14 @@ package evaluator
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/Youssef-Mak/baby-interpreter/pkg/ast"
 	"github.com/Youssef-Mak/baby-interpreter/pkg/object"
+	"github.com/Youssef-Mak/baby-interpreter/pkg/token"
 )
 
 var (
@@ -25,9 +30,13 @@ func init() {
 
 				switch arg := args[0].(type) {
 				case *object.String:
-					return &object.Integer{Value: int64(len(arg.Value))}
+					return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
 				case *object.Array:
 					return &object.Integer{Value: int64(len(arg.Elements))}
+				case *object.Set:
+					return &object.Integer{Value: int64(arg.Len())}
+				case *object.Hash:
+					return &object.Integer{Value: int64(arg.Len())}
 				default:
 					return newError("argument to `len` not supported, got %s", args[0].Type())
 				}
@@ -42,7 +51,11 @@ func init() {
 
 				switch arg := args[0].(type) {
 				case *object.String:
-					return &object.String{Value: string(arg.Value[0])}
+					runes := []rune(arg.Value)
+					if len(runes) == 0 {
+						return NULL
+					}
+					return &object.String{Value: string(runes[0])}
 				case *object.Array:
 					return arg.Elements[0]
 				default:
@@ -59,7 +72,11 @@ func init() {
 
 				switch arg := args[0].(type) {
 				case *object.String:
-					return &object.String{Value: string(arg.Value[len(arg.Value)-1])}
+					runes := []rune(arg.Value)
+					if len(runes) == 0 {
+						return NULL
+					}
+					return &object.String{Value: string(runes[len(runes)-1])}
 				case *object.Array:
 					return arg.Elements[len(arg.Elements)-1]
 				default:
@@ -87,6 +104,10 @@ func init() {
 					} else {
 						return TRUE
 					}
+				case *object.Set:
+					return boolToBooleanObject(arg.Len() == 0)
+				case *object.Hash:
+					return boolToBooleanObject(arg.Len() == 0)
 				default:
 					return newError("argument to `isEmpty` not supported, got %s", args[0].Type())
 				}
@@ -102,7 +123,8 @@ func init() {
 				switch arg := args[0].(type) {
 				case *object.String:
 					if len(arg.Value) > 0 {
-						return &object.String{Value: string(arg.Value[1:len(arg.Value)])}
+						_, size := utf8.DecodeRuneInString(arg.Value)
+						return &object.String{Value: arg.Value[size:]}
 					}
 				case *object.Array:
 					length := len(arg.Elements)
@@ -117,7 +139,7 @@ func init() {
 				return NULL
 			},
 		},
-		"get": { // Returns Element of Array at passed index
+		"get": { // Returns Element of Array at passed index, or value of Hash at passed key erroring if it is missing
 			Func: func(args ...object.Object) object.Object {
 				if len(args) != 2 {
 					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
@@ -137,6 +159,16 @@ func init() {
 						}
 						return arg.Elements[index.Value]
 					}
+				case *object.Hash:
+					key, ok := args[1].(object.Hashable)
+					if !ok {
+						return newError("expecting Hashable Type but got %s", args[1].Type())
+					}
+					value, found := arg.Get(key)
+					if !found {
+						return newError("key not found: %s", args[1].Inspect())
+					}
+					return value
 				default:
 					return newError("argument to `get` not supported, got %s", args[0].Type())
 				}
@@ -213,7 +245,7 @@ func init() {
 		},
 		"doWhile": { // Calls function returning a boolean until call resolves to false
 			Func: func(args ...object.Object) object.Object {
-				if len(args) > 1 {
+				if len(args) != 1 {
 					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
 						1, len(args))
 				}
@@ -221,14 +253,732 @@ func init() {
 				if !bodyOk {
 					return newError("arguments to `doWhile` not supported, expected Function, got %s", args[0].Type())
 				}
-				ret := TRUE
-				for ret == TRUE {
-					ret, _ = evalFunctionCall(body, nil).(*object.Boolean)
+				for {
+					ret := evalFunctionCall(body, nil)
+					if isError(ret) {
+						return ret
+					}
+					if ret != TRUE && ret != FALSE {
+						return newError("arguments to `doWhile` not supported, expected Function to return Boolean, got %s", ret.Type())
+					}
+					if ret == FALSE {
+						return ret
+					}
+				}
+			},
+		},
+		"set": { // Returns Set of the unique elements of an array
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+
+				switch arg := args[0].(type) {
+				case *object.Array:
+					return newSet(arg.Elements)
+				case *object.Set:
+					return newSet(arg.Elements())
+				default:
+					return newError("argument to `set` not supported, got %s", args[0].Type())
+				}
+			},
+		},
+		"has": { // Returns whether element is a member of set, or a key of hash
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+
+				switch arg := args[0].(type) {
+				case *object.Set:
+					member, ok := args[1].(object.Hashable)
+					if !ok {
+						return FALSE
+					}
+					return boolToBooleanObject(arg.Has(member))
+				case *object.Hash:
+					key, ok := args[1].(object.Hashable)
+					if !ok {
+						return FALSE
+					}
+					_, found := arg.Get(key)
+					return boolToBooleanObject(found)
+				default:
+					return newError("argument to `has` not supported, got %s", args[0].Type())
+				}
+			},
+		},
+		"keys": { // Returns Array of the keys of a hash in insertion order
+			Func: func(args ...object.Object) object.Object {
+				return hashEntriesArray("keys", args, func(entry object.HashEntry) object.Object {
+					return entry.Key
+				})
+			},
+		},
+		"values": { // Returns Array of the values of a hash in insertion order
+			Func: func(args ...object.Object) object.Object {
+				return hashEntriesArray("values", args, func(entry object.HashEntry) object.Object {
+					return entry.Value
+				})
+			},
+		},
+		"entries": { // Returns Array of the [key, value] pairs of a hash in insertion order
+			Func: func(args ...object.Object) object.Object {
+				return hashEntriesArray("entries", args, func(entry object.HashEntry) object.Object {
+					return &object.Array{Elements: []object.Object{entry.Key, entry.Value}}
+				})
+			},
+		},
+		"fromEntries": { // Returns Hash of an array of [key, value] pairs, later pairs overriding earlier ones
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+				pairs, ok := args[0].(*object.Array)
+				if !ok {
+					return newError("argument to `fromEntries` not supported, got %s", args[0].Type())
+				}
+				hash := object.NewHash()
+				for _, element := range pairs.Elements {
+					pair, isPair := element.(*object.Array)
+					if !isPair || len(pair.Elements) != 2 {
+						return newError("`fromEntries` expects [key, value] pairs, got %s", element.Inspect())
+					}
+					key, hashable := pair.Elements[0].(object.Hashable)
+					if !hashable {
+						return newError("This key is not Hashable : %s", pair.Elements[0].Inspect())
+					}
+					hash.Set(key, pair.Elements[1])
+				}
+				return hash
+			},
+		},
+		"delete": { // Returns Hash without the entry of key
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				hash, ok := args[0].(*object.Hash)
+				if !ok {
+					return newError("argument to `delete` not supported, got %s", args[0].Type())
+				}
+				key, hashable := args[1].(object.Hashable)
+				if !hashable {
+					return newError("expecting Hashable Type but got %s", args[1].Type())
+				}
+				deleted := object.NewHash()
+				for _, entry := range hash.Entries() {
+					if !object.Equal(entry.Key, key) {
+						deleted.Set(entry.Key.(object.Hashable), entry.Value)
+					}
+				}
+				return deleted
+			},
+		},
+		"merge": { // Returns Hash of the entries of every hash, values of later hashes overriding earlier ones
+			Func: func(args ...object.Object) object.Object {
+				if len(args) < 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected at least %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+				merged := object.NewHash()
+				for _, arg := range args {
+					hash, ok := arg.(*object.Hash)
+					if !ok {
+						return newError("argument to `merge` not supported, got %s", arg.Type())
+					}
+					for _, entry := range hash.Entries() {
+						merged.Set(entry.Key.(object.Hashable), entry.Value)
+					}
+				}
+				return merged
+			},
+		},
+		"toArray": { // Returns Array of the members of a set in insertion order
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+
+				switch arg := args[0].(type) {
+				case *object.Set:
+					return &object.Array{Elements: arg.Elements()}
+				case *object.Array:
+					return arg
+				default:
+					return newError("argument to `toArray` not supported, got %s", args[0].Type())
+				}
+			},
+		},
+		"clone": { // Returns a deep copy of the value
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+				return object.DeepCopy(args[0])
+			},
+		},
+		"freeze": { // Makes arrays, hashes and sets(and their contents) immutable
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+				return object.Freeze(args[0])
+			},
+		},
+		"isFrozen": { // Returns whether value is immutable
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+				return boolToBooleanObject(object.IsFrozen(args[0]))
+			},
+		},
+		"map": { // Returns Array of the results of calling function on each element
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				elems, fn, err := collectionAndFunction("map", args[0], args[1])
+				if err != nil {
+					return err
+				}
+				mapped := make([]object.Object, 0, len(elems))
+				for _, e := range elems {
+					res := evalFunctionCall(fn, []object.Object{e})
+					if isError(res) {
+						return res
+					}
+					mapped = append(mapped, res)
+				}
+				return &object.Array{Elements: mapped}
+			},
+		},
+		"filter": { // Returns Array of the elements for which function returns true
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				elems, fn, err := collectionAndFunction("filter", args[0], args[1])
+				if err != nil {
+					return err
+				}
+				filtered := []object.Object{}
+				for _, e := range elems {
+					res := evalFunctionCall(fn, []object.Object{e})
+					if isError(res) {
+						return res
+					}
+					if isTruthy(res) {
+						filtered = append(filtered, e)
+					}
+				}
+				return &object.Array{Elements: filtered}
+			},
+		},
+		"reduce": { // Returns accumulated value of calling function(acc, element) on each element starting from initial value
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 3 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						3, len(args))
+				}
+				elems, fn, err := collectionAndFunction("reduce", args[0], args[1])
+				if err != nil {
+					return err
+				}
+				acc := args[2]
+				for _, e := range elems {
+					acc = evalFunctionCall(fn, []object.Object{acc, e})
+					if isError(acc) {
+						return acc
+					}
+				}
+				return acc
+			},
+		},
+		"sortBy": { // Returns sorted Array using function(a, b) returning whether a comes before b
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				elems, fn, err := collectionAndFunction("sortBy", args[0], args[1])
+				if err != nil {
+					return err
+				}
+				sorted := make([]object.Object, len(elems))
+				copy(sorted, elems)
+				var callErr object.Object
+				sort.SliceStable(sorted, func(i, j int) bool {
+					if callErr != nil {
+						return false
+					}
+					res := evalFunctionCall(fn, []object.Object{sorted[i], sorted[j]})
+					if isError(res) {
+						callErr = res
+						return false
+					}
+					return isTruthy(res)
+				})
+				if callErr != nil {
+					return callErr
+				}
+				return &object.Array{Elements: sorted}
+			},
+		},
+		"any": { // Returns whether function returns true for at least one element
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				elems, fn, err := collectionAndFunction("any", args[0], args[1])
+				if err != nil {
+					return err
+				}
+				for _, e := range elems {
+					res := evalFunctionCall(fn, []object.Object{e})
+					if isError(res) {
+						return res
+					}
+					if isTruthy(res) {
+						return TRUE
+					}
+				}
+				return FALSE
+			},
+		},
+		"all": { // Returns whether function returns true for every element
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				elems, fn, err := collectionAndFunction("all", args[0], args[1])
+				if err != nil {
+					return err
+				}
+				for _, e := range elems {
+					res := evalFunctionCall(fn, []object.Object{e})
+					if isError(res) {
+						return res
+					}
+					if !isTruthy(res) {
+						return FALSE
+					}
+				}
+				return TRUE
+			},
+		},
+		"find": { // Returns the first element for which function returns true, null if none
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				elems, fn, err := collectionAndFunction("find", args[0], args[1])
+				if err != nil {
+					return err
+				}
+				for _, e := range elems {
+					res := evalFunctionCall(fn, []object.Object{e})
+					if isError(res) {
+						return res
+					}
+					if isTruthy(res) {
+						return e
+					}
+				}
+				return NULL
+			},
+		},
+		"zip": { // Returns Array of [a, b] pairs of elements at the same index, as long as the shortest array
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				left, leftOk := args[0].(*object.Array)
+				if !leftOk {
+					return newError("argument to `zip` not supported, got %s", args[0].Type())
+				}
+				right, rightOk := args[1].(*object.Array)
+				if !rightOk {
+					return newError("argument to `zip` not supported, got %s", args[1].Type())
+				}
+				length := len(left.Elements)
+				if len(right.Elements) < length {
+					length = len(right.Elements)
+				}
+				pairs := make([]object.Object, length)
+				for i := 0; i < length; i++ {
+					pairs[i] = &object.Array{Elements: []object.Object{left.Elements[i], right.Elements[i]}}
+				}
+				return &object.Array{Elements: pairs}
+			},
+		},
+		"range": { // Returns Array of Integers from start(default 0) up to but excluding end, by step(default 1)
+			Func: func(args ...object.Object) object.Object {
+				if len(args) < 1 || len(args) > 3 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d to %d arguments but got %d parameter(s)",
+						1, 3, len(args))
+				}
+				bounds := make([]int64, len(args))
+				for i, arg := range args {
+					integer, ok := arg.(*object.Integer)
+					if !ok {
+						return newError("argument to `range` not supported, got %s", arg.Type())
+					}
+					bounds[i] = integer.Value
+				}
+				start, end, step := int64(0), bounds[0], int64(1)
+				if len(bounds) > 1 {
+					start, end = bounds[0], bounds[1]
+				}
+				if len(bounds) > 2 {
+					step = bounds[2]
+				}
+				if step == 0 {
+					return newError("argument to `range` not supported, step cannot be 0")
+				}
+				elems := []object.Object{}
+				for i := start; (step > 0 && i < end) || (step < 0 && i > end); i += step {
+					elems = append(elems, &object.Integer{Value: i})
+				}
+				return &object.Array{Elements: elems}
+			},
+		},
+		"split": { // Returns Array of the substrings between each separator, of each character if separator is empty
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				strs, err := stringArgs("split", args)
+				if err != nil {
+					return err
+				}
+				parts := strings.Split(strs[0], strs[1])
+				elems := make([]object.Object, len(parts))
+				for i, part := range parts {
+					elems[i] = &object.String{Value: part}
+				}
+				return &object.Array{Elements: elems}
+			},
+		},
+		"join": { // Returns String of the strings of an array joined by separator
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				array, arrOk := args[0].(*object.Array)
+				if !arrOk {
+					return newError("argument to `join` not supported, got %s", args[0].Type())
+				}
+				strs, err := stringArgs("join", array.Elements)
+				if err != nil {
+					return err
+				}
+				sep, err := stringArgs("join", args[1:])
+				if err != nil {
+					return err
+				}
+				return &object.String{Value: strings.Join(strs, sep[0])}
+			},
+		},
+		"trim": { // Returns String without leading and trailing whitespace
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+				strs, err := stringArgs("trim", args)
+				if err != nil {
+					return err
+				}
+				return &object.String{Value: strings.TrimSpace(strs[0])}
+			},
+		},
+		"upper": { // Returns String with all letters in upper case
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+				strs, err := stringArgs("upper", args)
+				if err != nil {
+					return err
+				}
+				return &object.String{Value: strings.ToUpper(strs[0])}
+			},
+		},
+		"lower": { // Returns String with all letters in lower case
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+				strs, err := stringArgs("lower", args)
+				if err != nil {
+					return err
+				}
+				return &object.String{Value: strings.ToLower(strs[0])}
+			},
+		},
+		"replace": { // Returns String with every occurrence of old replaced by new
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 3 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						3, len(args))
+				}
+				strs, err := stringArgs("replace", args)
+				if err != nil {
+					return err
+				}
+				return &object.String{Value: strings.ReplaceAll(strs[0], strs[1], strs[2])}
+			},
+		},
+		"contains": { // Returns whether String contains substring
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				strs, err := stringArgs("contains", args)
+				if err != nil {
+					return err
+				}
+				return boolToBooleanObject(strings.Contains(strs[0], strs[1]))
+			},
+		},
+		"startsWith": { // Returns whether String begins with prefix
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				strs, err := stringArgs("startsWith", args)
+				if err != nil {
+					return err
+				}
+				return boolToBooleanObject(strings.HasPrefix(strs[0], strs[1]))
+			},
+		},
+		"endsWith": { // Returns whether String ends with suffix
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				strs, err := stringArgs("endsWith", args)
+				if err != nil {
+					return err
+				}
+				return boolToBooleanObject(strings.HasSuffix(strs[0], strs[1]))
+			},
+		},
+		"indexOf": { // Returns character index of the first occurrence of substring, -1 if absent
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				strs, err := stringArgs("indexOf", args)
+				if err != nil {
+					return err
+				}
+				idx := strings.Index(strs[0], strs[1])
+				if idx < 0 {
+					return &object.Integer{Value: -1}
+				}
+				return &object.Integer{Value: int64(utf8.RuneCountInString(strs[0][:idx]))}
+			},
+		},
+		"substring": { // Returns String of the characters from start up to but excluding end(default length)
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 && len(args) != 3 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d to %d arguments but got %d parameter(s)",
+						2, 3, len(args))
+				}
+				str, strOk := args[0].(*object.String)
+				if !strOk {
+					return newError("argument to `substring` not supported, got %s", args[0].Type())
+				}
+				runes := []rune(str.Value)
+				bounds := []int64{0, int64(len(runes))}
+				for i, arg := range args[1:] {
+					integer, ok := arg.(*object.Integer)
+					if !ok {
+						return newError("argument to `substring` not supported, expected Integer, got %s", arg.Type())
+					}
+					bounds[i] = integer.Value
+				}
+				if bounds[0] < 0 || bounds[1] > int64(len(runes)) || bounds[0] > bounds[1] {
+					return newError("substring bounds out of range [%d:%d] with length %d", bounds[0], bounds[1], len(runes))
+				}
+				return &object.String{Value: string(runes[bounds[0]:bounds[1]])}
+			},
+		},
+		"repeat": { // Returns String repeated count times
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 2 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						2, len(args))
+				}
+				str, strOk := args[0].(*object.String)
+				if !strOk {
+					return newError("argument to `repeat` not supported, got %s", args[0].Type())
+				}
+				count, countOk := args[1].(*object.Integer)
+				if !countOk {
+					return newError("argument to `repeat` not supported, expected Integer, got %s", args[1].Type())
+				}
+				if count.Value < 0 {
+					return newError("argument to `repeat` not supported, count cannot be negative, got %d", count.Value)
+				}
+				return &object.String{Value: strings.Repeat(str.Value, int(count.Value))}
+			},
+		},
+		"padLeft": { // Returns String padded at the start with pad(default " ") up to width characters
+			Func: func(args ...object.Object) object.Object {
+				return padString("padLeft", args, true)
+			},
+		},
+		"padRight": { // Returns String padded at the end with pad(default " ") up to width characters
+			Func: func(args ...object.Object) object.Object {
+				return padString("padRight", args, false)
+			},
+		},
+		"str": { // Returns String representation of the value
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+				if str, ok := args[0].(*object.String); ok {
+					return str
+				}
+				return &object.String{Value: args[0].Inspect()}
+			},
+		},
+		"type": { // Returns the name of the type of the value
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+				return &object.String{Value: typeName(args[0])}
+			},
+		},
+		"tag": { // Returns the name of the variant of a tagged union value, or of the struct
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+				structObj, ok := args[0].(*object.Struct)
+				if !ok {
+					return newError("argument to `tag` not supported, got %s", args[0].Type())
+				}
+				return &object.String{Value: structObj.Definition.Name}
+			},
+		},
+		"isInt":      typePredicate(object.INTEGER_OBJ),
+		"isBool":     typePredicate(object.BOOLEAN_OBJ),
+		"isString":   typePredicate(object.STRING_OBJ),
+		"isNull":     typePredicate(object.NULL_OBJ),
+		"isArray":    typePredicate(object.ARRAY_OBJ),
+		"isHash":     typePredicate(object.HASH_OBJ),
+		"isSet":      typePredicate(object.SET_OBJ),
+		"isFunction": typePredicate(object.FUNCTION_OBJ, object.BUILTIN_OBJ, object.STRUCT_DEF_OBJ),
+		"int": { // Converts String, Boolean or Integer to Integer
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+				switch arg := args[0].(type) {
+				case *object.Integer:
+					return arg
+				case *object.String:
+					value, err := strconv.ParseInt(strings.TrimSpace(arg.Value), 10, 64)
+					if err != nil {
+						return newError("cannot convert %q to Integer", arg.Value)
+					}
+					return &object.Integer{Value: value}
+				case *object.Boolean:
+					if arg.Value {
+						return &object.Integer{Value: 1}
+					}
+					return &object.Integer{Value: 0}
+				default:
+					return newError("cannot convert %s to Integer", typeName(arg))
+				}
+			},
+		},
+		"bool": { // Converts "true" or "false", Integer, Null or Boolean to Boolean
+			Func: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+				switch arg := args[0].(type) {
+				case *object.Boolean:
+					return arg
+				case *object.String:
+					switch strings.TrimSpace(arg.Value) {
+					case "true":
+						return TRUE
+					case "false":
+						return FALSE
+					default:
+						return newError("cannot convert %q to Boolean", arg.Value)
+					}
+				case *object.Integer:
+					return boolToBooleanObject(arg.Value != 0)
+				case *object.Null:
+					return FALSE
+				default:
+					return newError("cannot convert %s to Boolean", typeName(arg))
+				}
+			},
+		},
+		"format": { // Returns String of the format string with its verbs replaced by the formatted arguments
+			Func: func(args ...object.Object) object.Object {
+				if len(args) < 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected at least %d arguments but got %d parameter(s)",
+						1, len(args))
+				}
+				formatted, err := formatString("format", args[0], args[1:])
+				if err != nil {
+					return err
 				}
-				if ret != TRUE && ret != FALSE {
-					return newError("arguments to `doWhile` not supported, expected Function to return Boolean, got %s", ret.Type())
+				return &object.String{Value: formatted}
+			},
+		},
+		"printf": { // Prints the format string with its verbs replaced by the formatted arguments
+			Func: func(args ...object.Object) object.Object {
+				if len(args) < 1 {
+					return newError("Call Arguments and function defined parameters size mismatch.\n Expected at least %d arguments but got %d parameter(s)",
+						1, len(args))
 				}
-				return ret
+				formatted, err := formatString("printf", args[0], args[1:])
+				if err != nil {
+					return err
+				}
+				fmt.Print(formatted)
+				return NULL
 			},
 		},
 		"print": {
@@ -253,27 +1003,61 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(val) {
 			return val
 		}
-		env.Set(node.Name.Value, val, deepCopyFlag)
+		var stored object.Object
+		switch node.Token.Type {
+		case token.CONST:
+			stored, _ = env.DeclareConst(node.Name.Value, val, deepCopyFlag)
+		case token.LET:
+			stored, _ = env.Declare(node.Name.Value, val, deepCopyFlag)
+		default: // Re-assignment
+			stored, _ = env.Assign(node.Name.Value, val, deepCopyFlag)
+		}
+		if isError(stored) {
+			return stored
+		}
 	case *ast.BlockStatement:
 		return evalBlockStatement(node.Statements, env)
+	case *ast.FunctionDeclaration:
+		if stored, ok := env.Declare(node.Name.Value, evalFunctionLiteral(node.Function, env), false); !ok {
+			return stored
+		}
+	case *ast.StructDefinition:
+		if stored, ok := env.Declare(node.Name.Value, evalStructDefinition(node), false); !ok {
+			return stored
+		}
+	case *ast.TypeDefinition:
+		return evalTypeDefinition(node, env)
+	case *ast.FieldAssignmentStatement:
+		return evalFieldAssignment(node, env)
 	case *ast.ReturnStatement:
 		val := Eval(node.ReturnValue, env)
 		if isError(val) {
 			return val
 		}
 		return &object.ReturnValue{Value: val}
+	case *ast.ThrowStatement:
+		val := Eval(node.Value, env)
+		if isError(val) {
+			return val
+		}
+		return throwValue(val)
 	case *ast.ExpressionStatement:
 		return Eval(node.Expression, env)
 	case *ast.CallExpression:
-		function := Eval(node.Function, env)
+		function := evalCallee(node.Function, env)
 		if isError(function) {
 			return function
 		}
+		if function == NULL && isOptionalDot(node.Function) {
+			return NULL
+		}
 		args, err := evalExpressions(node.Arguments, env)
 		if err != nil {
 			return err
 		}
 		return evalFunctionCall(function, args)
+	case *ast.PipeExpression:
+		return evalPipeExpression(node, env)
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, env)
 		if isError(right) {
@@ -281,6 +1065,9 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		}
 		return evalPrefixExpression(node.Operator, right)
 	case *ast.InfixExpression:
+		if node.Operator == "??" {
+			return evalCoalesceExpression(node, env)
+		}
 		right := Eval(node.Right, env)
 		if isError(right) {
 			return right
@@ -300,20 +1087,40 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return index
 		}
 		return evalIndexExpression(left, index)
+	case *ast.SliceExpression:
+		left := Eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+		var start, end object.Object
+		if node.Start != nil {
+			start = Eval(node.Start, env)
+			if isError(start) {
+				return start
+			}
+		}
+		if node.End != nil {
+			end = Eval(node.End, env)
+			if isError(end) {
+				return end
+			}
+		}
+		return evalSliceExpression(left, start, end)
 	case *ast.DotExpression:
 		left := Eval(node.Left, env)
 		if isError(left) {
 			return left
 		}
-		attribute := Eval(node.Attribute, env)
-		if isError(attribute) {
-			return attribute
+		if left == NULL && node.Token.Type == token.OPTIONAL_DOT {
+			return NULL
 		}
-		return evalDotExpression(left, attribute)
+		return evalAttribute(left, node, env)
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
 	case *ast.WhileExpression:
 		return evalWhileExpression(node, env)
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
 	case *ast.FunctionLiteral:
 		return evalFunctionLiteral(node, env)
 	case *ast.Identifier:
@@ -324,8 +1131,16 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return error
 		}
 		return &object.Array{Elements: elements}
+	case *ast.TemplateLiteral:
+		return evalTemplateLiteral(node, env)
+	case *ast.SetLiteral:
+		elements, error := evalExpressions(node.Elements, env)
+		if error != nil {
+			return error
+		}
+		return newSet(elements)
 	case *ast.HashLiteral:
-		evalExprMap, error := evalMappedExpressions(node.Pairs, env)
+		evalExprMap, error := evalMappedExpressions(node, env)
 		if error != nil {
 			return error
 		}
@@ -344,7 +1159,13 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 func evalProgram(stmts []ast.Statement, env *object.Environment) object.Object {
 	var result object.Object
 
+	if err := hoistFunctionDeclarations(stmts, env); err != nil {
+		return err
+	}
 	for _, statement := range stmts {
+		if isHoisted(statement) { // Already declared
+			continue
+		}
 
 		result = Eval(statement, env)
 
@@ -374,15 +1195,16 @@ func evalExpressions(exprs []ast.Expression, env *object.Environment) ([]object.
 	return result, nil
 }
 
-func evalMappedExpressions(exprs map[ast.Expression]ast.Expression, env *object.Environment) (object.Object, object.Object) {
-	evaldMap := &object.Hash{Pairs: map[object.HashKey]object.HashEntry{}}
+func evalMappedExpressions(hl *ast.HashLiteral, env *object.Environment) (object.Object, object.Object) {
+	evaldMap := object.NewHash()
 
-	for keyExpr, valExpr := range exprs {
+	for _, keyExpr := range hl.Keys {
+		valExpr := hl.Pairs[keyExpr]
 		keyEvaled := Eval(keyExpr, env)
 		if isError(keyEvaled) {
 			return nil, keyEvaled
 		}
-		hashKey, ok := keyEvaled.(object.Hashable)
+		key, ok := keyEvaled.(object.Hashable)
 		if !ok {
 			return evaldMap, newError("This key is not Hashable : %s", keyEvaled.Inspect())
 		}
@@ -390,17 +1212,392 @@ func evalMappedExpressions(exprs map[ast.Expression]ast.Expression, env *object.
 		if isError(valEvaled) {
 			return nil, valEvaled
 		}
-		hEntry := object.HashEntry{Key: keyEvaled, Value: valEvaled}
-		evaldMap.Pairs[hashKey.HashKey()] = hEntry
+		evaldMap.Set(key, valEvaled)
 	}
 
 	return evaldMap, nil
 }
 
+// Builds Set from elements, errors if an element is not Hashable
+func newSet(elements []object.Object) object.Object {
+	set := object.NewSet()
+	for _, e := range elements {
+		member, ok := e.(object.Hashable)
+		if !ok {
+			return newError("This element is not Hashable : %s", e.Inspect())
+		}
+		set.Add(member)
+	}
+	return set
+}
+
+// Returns the name of the type of a value as shown to Baby programs
+func typeName(obj object.Object) string {
+	switch obj.Type() {
+	case object.INTEGER_OBJ:
+		return "Integer"
+	case object.BOOLEAN_OBJ:
+		return "Boolean"
+	case object.STRING_OBJ:
+		return "String"
+	case object.NULL_OBJ:
+		return "Null"
+	case object.ARRAY_OBJ:
+		return "Array"
+	case object.HASH_OBJ:
+		return "Hash"
+	case object.SET_OBJ:
+		return "Set"
+	case object.FUNCTION_OBJ, object.BUILTIN_OBJ, object.STRUCT_DEF_OBJ:
+		return "Function"
+	case object.STRUCT_OBJ:
+		if def := obj.(*object.Struct).Definition; def.Union != "" {
+			return def.Union
+		}
+		return obj.(*object.Struct).Definition.Name
+	case object.ERROR_OBJ:
+		return "Error"
+	default:
+		return string(obj.Type())
+	}
+}
+
+// Returns builtin checking whether its argument is of one of the types
+func typePredicate(types ...object.ObjectType) *object.BuiltIn {
+	return &object.BuiltIn{
+		Func: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+					1, len(args))
+			}
+			for _, objType := range types {
+				if args[0].Type() == objType {
+					return TRUE
+				}
+			}
+			return FALSE
+		},
+	}
+}
+
+// Returns String of the text and interpolated values of a template formatted with Inspect
+func evalTemplateLiteral(template *ast.TemplateLiteral, env *object.Environment) object.Object {
+	var out strings.Builder
+	for _, part := range template.Parts {
+		value := Eval(part, env)
+		if isError(value) {
+			return value
+		}
+		out.WriteString(value.Inspect())
+	}
+	return &object.String{Value: out.String()}
+}
+
+// Returns the format string with each verb replaced by its argument.
+// A verb is %[flags][width][.precision]<d|f|s|v> with flags among "-+0 ", %% is a literal percent sign
+func formatString(name string, format object.Object, args []object.Object) (string, object.Object) {
+	formatStr, ok := format.(*object.String)
+	if !ok {
+		return "", newError("argument to `%s` not supported, expected String, got %s", name, format.Type())
+	}
+
+	var out strings.Builder
+	spec := formatStr.Value
+	argIdx := 0
+	for i := 0; i < len(spec); i++ {
+		if spec[i] != '%' {
+			out.WriteByte(spec[i])
+			continue
+		}
+		start := i
+		i++
+		for i < len(spec) && strings.IndexByte("-+0 ", spec[i]) >= 0 {
+			i++
+		}
+		for i < len(spec) && isDigitByte(spec[i]) {
+			i++
+		}
+		if i < len(spec) && spec[i] == '.' {
+			i++
+			for i < len(spec) && isDigitByte(spec[i]) {
+				i++
+			}
+			if i < len(spec) && spec[i] == '.' {
+				return "", newError("`%s` verb %s has more than one precision", name, spec[start:i+1])
+			}
+		}
+		if i >= len(spec) {
+			return "", newError("`%s` string ends with an incomplete verb %q", name, spec[start:])
+		}
+		verb := spec[i]
+		if verb == '%' && i == start+1 {
+			out.WriteByte('%')
+			continue
+		}
+		if argIdx >= len(args) {
+			return "", newError("`%s` is missing an argument for verb %s", name, spec[start:i+1])
+		}
+		arg := args[argIdx]
+		argIdx++
+
+		goVerb := spec[start : i+1]
+		switch verb {
+		case 'd', 'f':
+			integer, isInt := arg.(*object.Integer)
+			if !isInt {
+				return "", newError("`%s` verb %s expects INTEGER, got %s", name, goVerb, arg.Type())
+			}
+			if verb == 'd' {
+				out.WriteString(fmt.Sprintf(goVerb, integer.Value))
+			} else {
+				out.WriteString(fmt.Sprintf(goVerb, float64(integer.Value)))
+			}
+		case 's', 'v':
+			out.WriteString(fmt.Sprintf(goVerb[:len(goVerb)-1]+"s", arg.Inspect()))
+		default:
+			return "", newError("`%s` has unknown verb %s", name, goVerb)
+		}
+	}
+	if argIdx < len(args) {
+		return "", newError("`%s` got %d arguments but format uses %d", name, len(args), argIdx)
+	}
+	return out.String(), nil
+}
+
+func isDigitByte(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+// Returns the values of String arguments of a builtin
+func stringArgs(name string, args []object.Object) ([]string, object.Object) {
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		str, ok := arg.(*object.String)
+		if !ok {
+			return nil, newError("argument to `%s` not supported, expected String, got %s", name, arg.Type())
+		}
+		strs[i] = str.Value
+	}
+	return strs, nil
+}
+
+// Returns String padded with pad repeated up to width characters
+func padString(name string, args []object.Object, left bool) object.Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d to %d arguments but got %d parameter(s)",
+			2, 3, len(args))
+	}
+	str, strOk := args[0].(*object.String)
+	if !strOk {
+		return newError("argument to `%s` not supported, got %s", name, args[0].Type())
+	}
+	width, widthOk := args[1].(*object.Integer)
+	if !widthOk {
+		return newError("argument to `%s` not supported, expected Integer, got %s", name, args[1].Type())
+	}
+	pad := []rune(" ")
+	if len(args) == 3 {
+		padStr, padOk := args[2].(*object.String)
+		if !padOk {
+			return newError("argument to `%s` not supported, expected String, got %s", name, args[2].Type())
+		}
+		if padStr.Value == "" {
+			return newError("argument to `%s` not supported, pad cannot be empty", name)
+		}
+		pad = []rune(padStr.Value)
+	}
+
+	missing := int(width.Value) - utf8.RuneCountInString(str.Value)
+	if missing <= 0 {
+		return str
+	}
+	padding := make([]rune, missing)
+	for i := range padding {
+		padding[i] = pad[i%len(pad)]
+	}
+	if left {
+		return &object.String{Value: string(padding) + str.Value}
+	}
+	return &object.String{Value: str.Value + string(padding)}
+}
+
+// Returns Array of the result of pick for each entry of the hash argument
+func hashEntriesArray(name string, args []object.Object, pick func(object.HashEntry) object.Object) object.Object {
+	if len(args) != 1 {
+		return newError("Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+			1, len(args))
+	}
+	hash, ok := args[0].(*object.Hash)
+	if !ok {
+		return newError("argument to `%s` not supported, got %s", name, args[0].Type())
+	}
+	elements := []object.Object{}
+	for _, entry := range hash.Entries() {
+		elements = append(elements, pick(entry))
+	}
+	return &object.Array{Elements: elements}
+}
+
+// Returns the elements of an array or set and the function to call on them
+func collectionAndFunction(name string, collection object.Object, fn object.Object) ([]object.Object, object.Object, object.Object) {
+	var elems []object.Object
+	switch collection := collection.(type) {
+	case *object.Array:
+		elems = collection.Elements
+	case *object.Set:
+		elems = collection.Elements()
+	default:
+		return nil, nil, newError("argument to `%s` not supported, got %s", name, collection.Type())
+	}
+	switch fn.(type) {
+	case *object.Function, *object.BuiltIn:
+		return elems, fn, nil
+	default:
+		return nil, nil, newError("argument to `%s` not supported, expected Function, got %s", name, fn.Type())
+	}
+}
+
+// Declares named functions before the statements of their scope run, allowing calls ahead of the declaration
+// and mutually recursive functions. Returns an error if a declaration rebinds a constant, including one declared
+// earlier in the same scope
+func hoistFunctionDeclarations(stmts []ast.Statement, env *object.Environment) object.Object {
+	consts := map[string]bool{}
+	for _, statement := range stmts {
+		switch statement := statement.(type) {
+		case *ast.AssignmentStatement:
+			if statement.Token.Type == token.CONST {
+				consts[statement.Name.Value] = true
+			}
+		case *ast.FunctionDeclaration, *ast.StructDefinition, *ast.TypeDefinition:
+			for _, name := range declaredNames(statement) {
+				if consts[name] {
+					return newError("cannot reassign constant: %s", name)
+				}
+			}
+			if result := Eval(statement, env); isError(result) {
+				return result
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the names bound by a function, struct or type declaration
+func declaredNames(statement ast.Statement) []string {
+	switch statement := statement.(type) {
+	case *ast.FunctionDeclaration:
+		return []string{statement.Name.Value}
+	case *ast.StructDefinition:
+		return []string{statement.Name.Value}
+	case *ast.TypeDefinition:
+		names := []string{}
+		for _, variant := range statement.Variants {
+			names = append(names, variant.Name.Value)
+		}
+		return names
+	default:
+		return nil
+	}
+}
+
+// Returns true for declarations evaluated ahead of the other statements of their scope
+func isHoisted(statement ast.Statement) bool {
+	switch statement.(type) {
+	case *ast.FunctionDeclaration, *ast.StructDefinition, *ast.TypeDefinition:
+		return true
+	default:
+		return false
+	}
+}
+
+func evalStructDefinition(def *ast.StructDefinition) object.Object {
+	fields := make([]string, len(def.Fields))
+	for i, f := range def.Fields {
+		fields[i] = f.Value
+	}
+	return &object.StructDefinition{Name: def.Name.Value, Fields: fields}
+}
+
+// Declares the constructor of each variant of a tagged union, variants without fields are declared as values
+func evalTypeDefinition(def *ast.TypeDefinition, env *object.Environment) object.Object {
+	for _, variant := range def.Variants {
+		fields := make([]string, len(variant.Fields))
+		for i, f := range variant.Fields {
+			fields[i] = f.Value
+		}
+		variantDef := &object.StructDefinition{Name: variant.Name.Value, Fields: fields, Union: def.Name.Value}
+		var variantValue object.Object = variantDef
+		if len(fields) == 0 {
+			variantValue = object.NewStruct(variantDef, []object.Object{})
+		}
+		if stored, ok := env.Declare(variant.Name.Value, variantValue, false); !ok {
+			return stored
+		}
+	}
+	return nil
+}
+
+// Returns value of the field of a struct named by an identifier
+func evalFieldAccess(structObj *object.Struct, attribute ast.Expression) object.Object {
+	field, ok := attribute.(*ast.Identifier)
+	if !ok {
+		return newError("expecting field name of %s but got %s", structObj.Definition.Name, attribute.String())
+	}
+	value, found := structObj.Get(field.Value)
+	if !found {
+		return newError("%s has no field %s", structObj.Definition.Name, field.Value)
+	}
+	return value
+}
+
+func evalFieldAssignment(node *ast.FieldAssignmentStatement, env *object.Environment) object.Object {
+	left := Eval(node.Target.Left, env)
+	if isError(left) {
+		return left
+	}
+	field := node.Target.Attribute.(*ast.Identifier).Value
+	switch target := left.(type) {
+	case *object.Struct:
+		if _, found := target.Get(field); !found {
+			return newError("%s has no field %s", target.Definition.Name, field)
+		}
+		if target.Frozen {
+			return newError("cannot mutate frozen %s: %s.%s", target.Definition.Name, node.Target.Left.String(), field)
+		}
+	case *object.Hash:
+		if target.Frozen {
+			return newError("cannot mutate frozen %s: %s.%s", target.Type(), node.Target.Left.String(), field)
+		}
+	default:
+		return newError("expecting Struct or Hash Type but got %s", left.Type())
+	}
+
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+	if node.Token.Literal != "=&" {
+		val = object.DeepCopy(val)
+	}
+	switch target := left.(type) {
+	case *object.Struct:
+		target.Set(field, val)
+	case *object.Hash:
+		target.Set(&object.String{Value: field}, val)
+	}
+	return nil
+}
+
 func evalBlockStatement(stmts []ast.Statement, env *object.Environment) object.Object {
 	var result object.Object
 
+	if err := hoistFunctionDeclarations(stmts, env); err != nil {
+		return err
+	}
 	for _, statement := range stmts {
+		if isHoisted(statement) { // Already declared
+			continue
+		}
 
 		result = Eval(statement, env)
 
@@ -438,14 +1635,16 @@ func evalInfixExpression(operator string, right object.Object, left object.Objec
 		return evalInfixIntegerExpression(operator, right, left)
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
 		return evalInfixStringExpression(operator, right, left)
+	case left.Type() == object.SET_OBJ && right.Type() == object.SET_OBJ:
+		return evalInfixSetExpression(operator, right, left)
 	case operator == "=&=":
 		return boolToBooleanObject(left == right)
 	case operator == "=*=":
-		return boolToBooleanObject(left.Inspect() == right.Inspect())
+		return boolToBooleanObject(object.Equal(left, right))
 	case operator == "!&=":
 		return boolToBooleanObject(left != right)
 	case operator == "!*=":
-		return boolToBooleanObject(left.Inspect() != right.Inspect())
+		return boolToBooleanObject(!object.Equal(left, right))
 	case operator == "&":
 		return boolToBooleanObject(left == TRUE && right == TRUE)
 	case operator == "|":
@@ -479,6 +1678,46 @@ func evalInfixStringExpression(operator string, right object.Object, left object
 	}
 }
 
+func evalInfixSetExpression(operator string, right object.Object, left object.Object) object.Object {
+	rightSet := right.(*object.Set)
+	leftSet := left.(*object.Set)
+
+	switch operator {
+	case "|": // Union
+		result := object.NewSet()
+		for _, member := range append(leftSet.Elements(), rightSet.Elements()...) {
+			result.Add(member.(object.Hashable))
+		}
+		return result
+	case "&": // Intersection
+		result := object.NewSet()
+		for _, member := range leftSet.Elements() {
+			if rightSet.Has(member.(object.Hashable)) {
+				result.Add(member.(object.Hashable))
+			}
+		}
+		return result
+	case "-": // Difference
+		result := object.NewSet()
+		for _, member := range leftSet.Elements() {
+			if !rightSet.Has(member.(object.Hashable)) {
+				result.Add(member.(object.Hashable))
+			}
+		}
+		return result
+	case "=*=":
+		return boolToBooleanObject(object.Equal(left, right))
+	case "!*=":
+		return boolToBooleanObject(!object.Equal(left, right))
+	case "=&=":
+		return boolToBooleanObject(left == right)
+	case "!&=":
+		return boolToBooleanObject(left != right)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
 func evalInfixIntegerExpression(operator string, right object.Object, left object.Object) object.Object {
 	rightVal := right.(*object.Integer).Value
 	leftVal := left.(*object.Integer).Value
@@ -515,25 +1754,119 @@ func evalInfixIntegerExpression(operator string, right object.Object, left objec
 	}
 }
 
+// Consequence and alternative are evaluated in their own block scope
 func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Object {
 	condition := Eval(ie.Condition, env)
+	if isError(condition) {
+		return condition
+	}
 
 	if isTruthy(condition) {
-		return Eval(ie.Consequence, env)
+		return Eval(ie.Consequence, object.NewEnclosedEnvironment(env))
 	} else if ie.Alternative != nil {
-		return Eval(ie.Alternative, env)
+		return Eval(ie.Alternative, object.NewEnclosedEnvironment(env))
 	} else {
 		return NULL
 	}
 }
 
+// Evaluates to the value of the try block, or of the catch block when the try block raises an error.
+// The finally block always runs after them
+func evalTryExpression(te *ast.TryExpression, env *object.Environment) object.Object {
+	result := Eval(te.Block, object.NewEnclosedEnvironment(env))
+	if err, ok := result.(*object.Error); ok && te.Catch != nil {
+		catchEnv := object.NewEnclosedEnvironment(env)
+		catchEnv.Declare(te.Param.Value, caughtError(err), false)
+		result = Eval(te.Catch, catchEnv)
+	}
+
+	if te.Finally != nil {
+		final := Eval(te.Finally, object.NewEnclosedEnvironment(env))
+		// Errors and return statements of the finally block replace the result
+		if isError(final) || (final != nil && final.Type() == object.RETURN_VAL_OBJ) {
+			return final
+		}
+	}
+	if result == nil {
+		return NULL
+	}
+	return result
+}
+
+// Returns the error raised by throwing a value, its message is the thrown value or the "message" of a thrown hash.
+// Rethrowing an error received by a catch block raises that error again
+func throwValue(value object.Object) *object.Error {
+	if err, ok := rethrownError(value); ok {
+		return err
+	}
+	message := value.Inspect()
+	if hash, ok := value.(*object.Hash); ok {
+		if msg, found := hash.Get(&object.String{Value: "message"}); found {
+			message = msg.Inspect()
+		}
+	}
+	return &object.Error{Message: message, Value: value}
+}
+
+// Returns the value received by a catch block, a hash of the message, type, stack and thrown value of the error.
+// The type is "Error" unless the thrown hash has a "type"
+func caughtError(err *object.Error) *object.Hash {
+	errType := "Error"
+	if hash, ok := err.Value.(*object.Hash); ok {
+		if declared, found := hash.Get(&object.String{Value: "type"}); found {
+			if name, isString := declared.(*object.String); isString {
+				errType = name.Value
+			}
+		}
+	}
+	stack := []object.Object{}
+	for _, frame := range err.Stack {
+		stack = append(stack, &object.String{Value: frame})
+	}
+	value := err.Value
+	if value == nil {
+		value = NULL
+	}
+
+	caught := object.NewHash()
+	caught.Set(&object.String{Value: "message"}, &object.String{Value: err.Message})
+	caught.Set(&object.String{Value: "type"}, &object.String{Value: errType})
+	caught.Set(&object.String{Value: "stack"}, &object.Array{Elements: stack})
+	caught.Set(&object.String{Value: "value"}, value)
+	caught.Caught = err
+	return caught
+}
+
+// Returns the error a catch block received as value, with a copy of its stack so the frames it unwinds
+// through next are added after the original ones
+func rethrownError(value object.Object) (*object.Error, bool) {
+	hash, ok := value.(*object.Hash)
+	if !ok || hash.Caught == nil {
+		return nil, false
+	}
+	caught := hash.Caught
+	stack := append([]string{}, caught.Stack...)
+	return &object.Error{Message: caught.Message, Value: caught.Value, Stack: stack}, true
+}
+
+// Every iteration of the body is evaluated in a fresh block scope
 func evalWhileExpression(we *ast.WhileExpression, env *object.Environment) object.Object {
 	condition := Eval(we.Condition, env)
 	ret := object.ReturnValue{Value: NULL}
-	for isTruthy(condition) {
-		ret.Value = Eval(we.Body, env)
+	for !isError(condition) && isTruthy(condition) {
+		ret.Value = Eval(we.Body, object.NewEnclosedEnvironment(env))
+		// Errors and return statements end the loop
+		if isError(ret.Value) {
+			return ret.Value
+		}
+		if ret.Value != nil && ret.Value.Type() == object.RETURN_VAL_OBJ {
+			return ret.Value
+		}
 		condition = Eval(we.Condition, env)
 	}
+	if isError(condition) {
+		return condition
+	}
 	return ret.Value
 }
 
@@ -548,17 +1881,115 @@ func evalFunctionCall(funcCalled object.Object, args []object.Object) object.Obj
 		}
 		funcScope := object.NewEnclosedEnvironment(funcCalled.Env)
 		for idx, param := range funcCalled.Parameters {
-			funcScope.Set(param.Value, args[idx], false)
+			funcScope.Declare(param.Value, args[idx], false)
 		}
 		evaluatedRes := Eval(funcCalled.Body, funcScope)
+		if err, ok := evaluatedRes.(*object.Error); ok {
+			err.Stack = append(err.Stack, functionName(funcCalled))
+		}
 		return unwrapReturnValue(evaluatedRes)
 	case *object.BuiltIn:
 		return funcCalled.Func(args...)
+	case *object.StructDefinition:
+		if len(args) != len(funcCalled.Fields) {
+			return newError(
+				"Call Arguments and function defined parameters size mismatch.\n Expected %d arguments but got %d parameter(s)",
+				len(funcCalled.Fields), len(args))
+		}
+		values := make([]object.Object, len(args))
+		copy(values, args)
+		return object.NewStruct(funcCalled, values)
 	default:
 		return newError("Is not Callable (not a recognized function): %s", funcCalled.Type())
 	}
 }
 
+// Returns the result of calling the right side of the pipe with the left value as first argument
+func evalPipeExpression(pipe *ast.PipeExpression, env *object.Environment) object.Object {
+	left := Eval(pipe.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	call, isCall := pipe.Right.(*ast.CallExpression)
+	if !isCall {
+		function := evalCallee(pipe.Right, env)
+		if isError(function) {
+			return function
+		}
+		if function == NULL && isOptionalDot(pipe.Right) {
+			return NULL
+		}
+		return evalFunctionCall(function, []object.Object{left})
+	}
+
+	function := evalCallee(call.Function, env)
+	if isError(function) {
+		return function
+	}
+	if function == NULL && isOptionalDot(call.Function) {
+		return NULL
+	}
+	args, err := evalExpressions(call.Arguments, env)
+	if err != nil {
+		return err
+	}
+	return evalFunctionCall(function, append([]object.Object{left}, args...))
+}
+
+// Returns whether the expression is an optional dot expression "<expression>?.<attribute>",
+// calling one that is null evaluates to null
+func isOptionalDot(expr ast.Expression) bool {
+	dot, ok := expr.(*ast.DotExpression)
+	return ok && dot.Token.Type == token.OPTIONAL_DOT
+}
+
+// Returns the left value unless it is null, the right side is only evaluated when needed
+func evalCoalesceExpression(node *ast.InfixExpression, env *object.Environment) object.Object {
+	left := Eval(node.Left, env)
+	if left != NULL {
+		return left
+	}
+	return Eval(node.Right, env)
+}
+
+// Returns the function called by a call expression, functions called as "<receiver>.<attribute>(<args>)"
+// are methods with the receiver bound to self
+func evalCallee(callee ast.Expression, env *object.Environment) object.Object {
+	dot, isMethod := callee.(*ast.DotExpression)
+	if !isMethod {
+		return Eval(callee, env)
+	}
+
+	receiver := Eval(dot.Left, env)
+	if isError(receiver) {
+		return receiver
+	}
+	if receiver == NULL && dot.Token.Type == token.OPTIONAL_DOT {
+		return NULL
+	}
+	method := evalAttribute(receiver, dot, env)
+	if function, ok := method.(*object.Function); ok {
+		return bindSelf(function, receiver)
+	}
+	return method
+}
+
+// Returns a copy of the function whose body sees the receiver as self
+func bindSelf(function *object.Function, receiver object.Object) *object.Function {
+	methodEnv := object.NewEnclosedEnvironment(function.Env)
+	methodEnv.Declare("self", receiver, false)
+	return &object.Function{Name: function.Name, Parameters: function.Parameters, Body: function.Body, Env: methodEnv}
+}
+
+// Name of function for stack traces
+func functionName(fun *object.Function) string {
+	if fun.Name == "" {
+		return "<anonymous>"
+	}
+	return fun.Name
+}
+
 func unwrapReturnValue(obj object.Object) object.Object {
 	if returnValue, ok := obj.(*object.ReturnValue); ok {
 		return returnValue.Value
@@ -567,18 +1998,112 @@ func unwrapReturnValue(obj object.Object) object.Object {
 }
 
 func evalIndexExpression(left object.Object, index object.Object) object.Object {
-	array, arrOk := left.(*object.Array)
-	if !arrOk {
-		return newError("expecting Array Type but got %s", left.Type())
-	}
 	idx, idxOk := index.(*object.Integer)
-	if !idxOk {
-		return newError("expecting Integer Type but got %s", index.Type())
+	switch left := left.(type) {
+	case *object.Array:
+		if !idxOk {
+			return newError("expecting Integer Type but got %s", index.Type())
+		}
+		i, inBounds := indexPosition(idx.Value, len(left.Elements))
+		if !inBounds {
+			return NULL
+		}
+		return left.Elements[i]
+	case *object.String:
+		if !idxOk {
+			return newError("expecting Integer Type but got %s", index.Type())
+		}
+		runes := []rune(left.Value)
+		i, inBounds := indexPosition(idx.Value, len(runes))
+		if !inBounds {
+			return NULL
+		}
+		return &object.String{Value: string(runes[i])}
+	case *object.Hash:
+		return evalDotExpression(left, index)
+	default:
+		return newError("expecting Array, String or Hash Type but got %s", left.Type())
 	}
-	if idx.Value > int64(len(array.Elements)-1) || idx.Value < 0 {
-		return NULL
+}
+
+// Returns position of index counting from the end when negative, and whether it is within length
+func indexPosition(index int64, length int) (int64, bool) {
+	if index < 0 {
+		index += int64(length)
+	}
+	return index, index >= 0 && index < int64(length)
+}
+
+// Returns the part of an array or string from start up to but excluding end
+func evalSliceExpression(left object.Object, start object.Object, end object.Object) object.Object {
+	var length int
+	switch left := left.(type) {
+	case *object.Array:
+		length = len(left.Elements)
+	case *object.String:
+		length = utf8.RuneCountInString(left.Value)
+	default:
+		return newError("expecting Array or String Type but got %s", left.Type())
+	}
+
+	from, err := sliceBound(start, 0, length)
+	if err != nil {
+		return err
+	}
+	to, err := sliceBound(end, length, length)
+	if err != nil {
+		return err
+	}
+	if from > to {
+		from = to
+	}
+
+	switch left := left.(type) {
+	case *object.Array:
+		elems := make([]object.Object, to-from)
+		copy(elems, left.Elements[from:to])
+		return &object.Array{Elements: elems}
+	default:
+		runes := []rune(left.(*object.String).Value)
+		return &object.String{Value: string(runes[from:to])}
+	}
+}
+
+// Returns slice bound counting from the end when negative and clamped to the length, fallback when omitted
+func sliceBound(bound object.Object, fallback int, length int) (int, object.Object) {
+	if bound == nil {
+		return fallback, nil
+	}
+	integer, ok := bound.(*object.Integer)
+	if !ok {
+		return 0, newError("expecting Integer Type but got %s", bound.Type())
+	}
+	position := integer.Value
+	if position < 0 {
+		position += int64(length)
+	}
+	if position < 0 {
+		return 0, nil
+	}
+	if position > int64(length) {
+		return length, nil
+	}
+	return int(position), nil
+}
+
+// Returns the value of the attribute of a dot expression on an already evaluated left side
+func evalAttribute(left object.Object, dot *ast.DotExpression, env *object.Environment) object.Object {
+	if structObj, ok := left.(*object.Struct); ok {
+		return evalFieldAccess(structObj, dot.Attribute)
+	}
+	if name, ok := dot.Attribute.(*ast.Identifier); ok { // ".name" is the key "name"
+		return evalDotExpression(left, &object.String{Value: name.Value})
+	}
+	attribute := Eval(dot.Attribute, env)
+	if isError(attribute) {
+		return attribute
 	}
-	return array.Elements[idx.Value]
+	return evalDotExpression(left, attribute)
 }
 
 func evalDotExpression(left object.Object, attribute object.Object) object.Object {
@@ -591,11 +2116,11 @@ func evalDotExpression(left object.Object, attribute object.Object) object.Objec
 		return newError("expecting Hashable Type but got %s", attribute.Type())
 	}
 
-	pair, found := hash.Pairs[attr.HashKey()]
+	value, found := hash.Get(attr)
 	if !found {
 		return NULL
 	}
-	return pair.Value
+	return value
 }
 
 func isTruthy(ob object.Object) bool {
@@ -635,6 +2160,7 @@ func evalNegativeOperatorExpression(right object.Object) object.Object {
 
 func evalFunctionLiteral(fun *ast.FunctionLiteral, env *object.Environment) object.Object {
 	res := object.Function{
+		Name:       fun.Name,
 		Parameters: fun.Parameters,
 		Body:       fun.Body,
 		Env:        env,
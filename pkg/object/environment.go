@@ -1,13 +1,17 @@
 package object
 
+import "fmt"
+
 type Environment struct {
-	store map[string]*Object
-	outer *Environment
+	store  map[string]*Object
+	consts map[string]bool // Identifiers declared with const
+	outer  *Environment
 }
 
 func NewEnvironment() *Environment {
 	s := make(map[string]*Object)
-	return &Environment{store: s, outer: nil}
+	c := make(map[string]bool)
+	return &Environment{store: s, consts: c, outer: nil}
 }
 
 func NewEnclosedEnvironment(outer *Environment) *Environment {
@@ -25,57 +29,105 @@ func (e *Environment) Get(id string) (*Object, bool) {
 	return obj, ok
 }
 
-// Set object in the environment with given ID
-// Reference flag serves as indication to either deep or shallow copy the object
-func (e *Environment) Set(id string, obj Object, deepCopy bool) (Object, bool) {
-	objRef, found := e.Get(id) // Check if this is reassignment operation
-	if found && deepCopy {     // No change to the address, only change the value
+// Returns true if id is bound to a constant
+func (e *Environment) IsConst(id string) bool {
+	if _, ok := e.store[id]; ok {
+		return e.consts[id]
+	}
+	if e.outer != nil {
+		return e.outer.IsConst(id)
+	}
+	return false
+}
+
+// Declare object in the current scope with given ID, shadowing bindings of outer scopes
+// Deep copy flag serves as indication to either deep or shallow copy the object
+// Refusals(constants) are reported by returning an Error
+func (e *Environment) Declare(id string, obj Object, deepCopy bool) (Object, bool) {
+	if e.consts[id] {
+		return &Error{Message: fmt.Sprintf("cannot reassign constant: %s", id)}, false
+	}
+	if deepCopy {
+		obj = DeepCopy(obj)
+	}
+	e.store[id] = &obj
+	delete(e.consts, id)
+	return obj, true
+}
+
+// Declare constant in the current scope with given ID, constants can never be rebound
+func (e *Environment) DeclareConst(id string, obj Object, deepCopy bool) (Object, bool) {
+	stored, ok := e.Declare(id, obj, deepCopy)
+	if ok {
+		e.consts[id] = true
+	}
+	return stored, ok
+}
+
+// Assign object to the existing binding of ID in the scope defining it
+// Deep copies keep the address of the bound object and only change its value, shallow copies rebind the ID
+// Refusals(undeclared identifiers, constants, frozen values) are reported by returning an Error
+func (e *Environment) Assign(id string, obj Object, deepCopy bool) (Object, bool) {
+	scope := e.definingScope(id)
+	if scope == nil {
+		return &Error{Message: fmt.Sprintf("Identifier not declared: %s", id)}, false
+	}
+	if scope.consts[id] {
+		return &Error{Message: fmt.Sprintf("cannot reassign constant: %s", id)}, false
+	}
+	objRef := scope.store[id]
+	if deepCopy {
 		objStored := *objRef
-		if objStored.Type() != obj.Type() {
-			return nil, false
+		if IsFrozen(objStored) {
+			return &Error{Message: fmt.Sprintf("cannot mutate frozen %s: %s", objStored.Type(), id)}, false
 		}
-		// TODO: look into a different way of doing this
-		switch obj.Type() {
-		case ARRAY_OBJ:
-			objArr, ok := (objStored).(*Array)
-			if ok {
-				objArr.Elements = obj.(*Array).Elements
-			}
-			return obj, true
-		case STRING_OBJ:
-			objStr, ok := objStored.(*String)
-			if ok {
-				objStr.Value = obj.(*String).Value
-			}
-			return obj, true
-		case INTEGER_OBJ:
-			objInt, ok := objStored.(*Integer)
-			if ok {
-				objInt.Value = obj.(*Integer).Value
-			}
-			return obj, true
-		case BOOLEAN_OBJ:
-			objBool, ok := objStored.(*Boolean)
-			if ok {
-				objBool.Value = obj.(*Boolean).Value
-			}
-			return obj, true
-		case HASH_OBJ:
-			hashObj, ok := objStored.(*Hash)
-			if ok {
-				hashObj.Pairs = obj.(*Hash).Pairs
-			}
-			return obj, true
-		default:
-			break
+		copied := DeepCopy(obj)
+		if !overwrite(objStored, copied) {
+			*objRef = copied
 		}
-	} else if !found && deepCopy {
-		newObj := obj
-		e.store[id] = &newObj
-		return newObj, true
+		return *objRef, true
 	}
 	// Shallow-Copy
-	e.store[id] = &obj
+	scope.store[id] = &obj
 	return obj, true
+}
+
+// Returns innermost scope in which id is bound, nil if unbound
+func (e *Environment) definingScope(id string) *Environment {
+	for scope := e; scope != nil; scope = scope.outer {
+		if _, ok := scope.store[id]; ok {
+			return scope
+		}
+	}
+	return nil
+}
 
+// Replaces the value of dst by the value of src while keeping the address of dst
+// Returns false if dst has no value to replace(singletons, functions) or src is of a different type
+func overwrite(dst Object, src Object) bool {
+	if dst.Type() != src.Type() {
+		return false
+	}
+	switch dst := dst.(type) {
+	case *Integer:
+		dst.Value = src.(*Integer).Value
+	case *String:
+		dst.Value = src.(*String).Value
+		dst.hashKey = nil
+	case *Array:
+		dst.Elements = src.(*Array).Elements
+	case *Hash:
+		dst.Pairs = src.(*Hash).Pairs
+		dst.Order = src.(*Hash).Order
+	case *Set:
+		dst.Members = src.(*Set).Members
+	case *Struct:
+		if dst.Definition != src.(*Struct).Definition {
+			return false
+		}
+		dst.Values = src.(*Struct).Values
+	default:
+		return false
+	}
+	return true
 }
@@ -2,7 +2,9 @@ package object
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
+	"hash"
 	"hash/fnv"
 	"strings"
 
@@ -22,6 +24,9 @@ const (
 	ERROR_OBJ      = "ERROR"
 	ARRAY_OBJ      = "ARRAY"
 	HASH_OBJ       = "HASH"
+	SET_OBJ        = "SET"
+	STRUCT_DEF_OBJ = "STRUCT_DEFINITION"
+	STRUCT_OBJ     = "STRUCT"
 )
 
 type Object interface {
@@ -29,8 +34,11 @@ type Object interface {
 	Inspect() string
 }
 
-// TODO: Cache Hash Values for all implementations
+// Objects that can be used as keys of a Hash
+// Implementations with costly hashes(String) cache their HashKey
+// Containers(Array, Hash) are hashed structurally and stored as immutable snapshots when used as keys
 type Hashable interface {
+	Object
 	HashKey() HashKey
 }
 
@@ -65,37 +73,103 @@ func (b *Boolean) HashKey() HashKey {
 }
 
 type String struct {
-	Value string
+	Value   string
+	hashKey *HashKey // Cached HashKey, reset whenever Value changes
 }
 
 func (s *String) Type() ObjectType { return STRING_OBJ }
 func (s *String) Inspect() string  { return s.Value }
 
-// TODO: Handle hash collision(open addressing or seperate chaining)
+// Collisions are handled by the Hash itself(seperate chaining)
 func (s *String) HashKey() HashKey {
+	if s.hashKey != nil {
+		return *s.hashKey
+	}
+
 	hash := fnv.New64a()
 	hash.Write([]byte(s.Value))
 
-	return HashKey{Type: s.Type(), Value: hash.Sum64()}
+	s.hashKey = &HashKey{Type: s.Type(), Value: hash.Sum64()}
+	return *s.hashKey
 }
 
 type Array struct {
 	Elements []Object
+	Frozen   bool // Frozen arrays can not be mutated
 }
 
 func (ao *Array) Type() ObjectType { return ARRAY_OBJ }
-func (ao *Array) Inspect() string {
+func (ao *Array) Inspect() string  { return inspect(ao, map[Object]bool{}) }
+
+// Inspects containers tracking the containers being inspected, a container reached again inside itself(a cycle)
+// is shown as [...], {...}, #{...} or <struct>{...} instead of its contents again
+func inspect(obj Object, visiting map[Object]bool) string {
 	var out bytes.Buffer
-	elements := []string{}
-	for _, e := range ao.Elements {
-		elements = append(elements, e.Inspect())
+	switch obj := obj.(type) {
+	case *Array:
+		if visiting[obj] {
+			return "[...]"
+		}
+		visiting[obj] = true
+		defer delete(visiting, obj)
+		elements := []string{}
+		for _, e := range obj.Elements {
+			elements = append(elements, inspect(e, visiting))
+		}
+		out.WriteString("[")
+		out.WriteString(strings.Join(elements, ", "))
+		out.WriteString("]")
+	case *Hash:
+		if visiting[obj] {
+			return "{...}"
+		}
+		visiting[obj] = true
+		defer delete(visiting, obj)
+		pairsMsg := []string{}
+		for _, entry := range obj.Entries() {
+			pairsMsg = append(pairsMsg, inspect(entry.Key, visiting)+" : "+inspect(entry.Value, visiting))
+		}
+		out.WriteString("{")
+		out.WriteString(strings.Join(pairsMsg, ", "))
+		out.WriteString("}")
+	case *Set:
+		if visiting[obj] {
+			return "#{...}"
+		}
+		visiting[obj] = true
+		defer delete(visiting, obj)
+		elements := []string{}
+		for _, e := range obj.Elements() {
+			elements = append(elements, inspect(e, visiting))
+		}
+		out.WriteString("#{")
+		out.WriteString(strings.Join(elements, ", "))
+		out.WriteString("}")
+	case *Struct:
+		if obj.Definition.Union != "" && len(obj.Values) == 0 {
+			return obj.Definition.Name
+		}
+		if visiting[obj] {
+			return obj.Definition.Name + "{...}"
+		}
+		visiting[obj] = true
+		defer delete(visiting, obj)
+		fields := []string{}
+		for i, field := range obj.Definition.Fields {
+			fields = append(fields, field+": "+inspect(obj.Values[i], visiting))
+		}
+		out.WriteString(obj.Definition.Name + "{" + strings.Join(fields, ", ") + "}")
+	default:
+		return obj.Inspect()
 	}
-	out.WriteString("[")
-	out.WriteString(strings.Join(elements, ", "))
-	out.WriteString("]")
 	return out.String()
 }
 
+// Structural hash of the elements, consistent with Equal
+func (ao *Array) HashKey() HashKey {
+	return structuralHashKey(ao, map[Object]int{})
+}
+
 type HashKey struct {
 	Type  ObjectType
 	Value uint64
@@ -106,23 +180,434 @@ type HashEntry struct {
 	Value Object
 }
 
+// Entries sharing a HashKey are chained in the same bucket and told apart by key equality
 type Hash struct {
-	Pairs map[HashKey]HashEntry
+	Pairs  map[HashKey][]*HashEntry // Buckets of entries
+	Order  []*HashEntry             // Entries in insertion order
+	Frozen bool                     // Frozen hashes can not be mutated
+	Caught *Error                   // Error a catch block received as this hash, nil for other hashes
+}
+
+func NewHash() *Hash {
+	return &Hash{Pairs: map[HashKey][]*HashEntry{}, Order: []*HashEntry{}}
+}
+
+// Returns value stored at key
+func (h *Hash) Get(key Hashable) (Object, bool) {
+	for _, entry := range h.Pairs[key.HashKey()] {
+		if keysEqual(entry.Key, key) {
+			return entry.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Sets value at key, an existing key keeps its original position
+func (h *Hash) Set(key Hashable, value Object) {
+	hashKey := key.HashKey()
+	for _, entry := range h.Pairs[hashKey] {
+		if keysEqual(entry.Key, key) {
+			entry.Value = value
+			return
+		}
+	}
+	entry := &HashEntry{Key: snapshotKey(key), Value: value}
+	h.Pairs[hashKey] = append(h.Pairs[hashKey], entry)
+	h.Order = append(h.Order, entry)
+}
+
+// Returns number of entries
+func (h *Hash) Len() int {
+	return len(h.Order)
+}
+
+// Returns entries in insertion order, keys are snapshots so they can not be overwritten through the entries
+func (h *Hash) Entries() []HashEntry {
+	entries := make([]HashEntry, 0, len(h.Order))
+	for _, entry := range h.Order {
+		entries = append(entries, HashEntry{Key: snapshotKey(entry.Key.(Hashable)), Value: entry.Value})
+	}
+	return entries
 }
 
 func (h *Hash) Type() ObjectType { return HASH_OBJ }
 
-func (h *Hash) Inspect() string {
-	var out bytes.Buffer
-	pairsMsg := []string{}
-	for _, entry := range h.Pairs {
-		pairsMsg = append(pairsMsg, entry.Key.Inspect()+" : "+entry.Value.Inspect())
+func (h *Hash) Inspect() string { return inspect(h, map[Object]bool{}) }
+
+// Structural hash of the entries independent of insertion order, consistent with Equal
+func (h *Hash) HashKey() HashKey {
+	return structuralHashKey(h, map[Object]int{})
+}
+
+// Unordered collection of unique Hashable members, backed by a Hash mapping each member to itself
+type Set struct {
+	Members *Hash
+	Frozen  bool // Frozen sets can not be mutated
+}
+
+func NewSet() *Set {
+	return &Set{Members: NewHash()}
+}
+
+// Adds member, members already present keep their original position
+func (s *Set) Add(member Hashable) {
+	if !s.Has(member) {
+		s.Members.Set(member, member)
+	}
+}
+
+// Returns true if member is in the set
+func (s *Set) Has(member Hashable) bool {
+	_, found := s.Members.Get(member)
+	return found
+}
+
+// Returns number of members
+func (s *Set) Len() int {
+	return s.Members.Len()
+}
+
+// Returns members in insertion order, as snapshots so they can not be overwritten through the elements
+func (s *Set) Elements() []Object {
+	elements := make([]Object, 0, s.Len())
+	for _, entry := range s.Members.Order {
+		elements = append(elements, snapshotKey(entry.Key.(Hashable)))
+	}
+	return elements
+}
+
+func (s *Set) Type() ObjectType { return SET_OBJ }
+func (s *Set) Inspect() string  { return inspect(s, map[Object]bool{}) }
+
+// Structural hash of the members independent of insertion order, consistent with Equal
+func (s *Set) HashKey() HashKey {
+	return structuralHashKey(s, map[Object]int{})
+}
+
+// Declared by "struct <name> { <fields> }" or as a variant of "type <union> = <variants>",
+// calling it constructs a Struct
+type StructDefinition struct {
+	Name   string
+	Fields []string
+	Union  string // Name of the tagged union declaring this variant, empty for structs
+}
+
+func (sd *StructDefinition) Type() ObjectType { return STRUCT_DEF_OBJ }
+func (sd *StructDefinition) Inspect() string {
+	if sd.Union != "" {
+		return sd.Union + "." + sd.Name + "(" + strings.Join(sd.Fields, ", ") + ")"
+	}
+	return "struct " + sd.Name + " { " + strings.Join(sd.Fields, ", ") + " }"
+}
+
+// Returns position of field in the values of the structs of this definition
+func (sd *StructDefinition) FieldIndex(field string) (int, bool) {
+	for i, f := range sd.Fields {
+		if f == field {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// Record with the fixed fields of its definition
+type Struct struct {
+	Definition *StructDefinition
+	Values     []Object // Values of the fields in the order of the definition
+	Frozen     bool     // Frozen structs can not be mutated
+}
+
+func NewStruct(definition *StructDefinition, values []Object) *Struct {
+	return &Struct{Definition: definition, Values: values}
+}
+
+// Returns value of field
+func (s *Struct) Get(field string) (Object, bool) {
+	idx, ok := s.Definition.FieldIndex(field)
+	if !ok {
+		return nil, false
+	}
+	return s.Values[idx], true
+}
+
+// Sets value of field, returns false if the definition has no such field
+func (s *Struct) Set(field string, value Object) bool {
+	idx, ok := s.Definition.FieldIndex(field)
+	if !ok {
+		return false
+	}
+	s.Values[idx] = value
+	return true
+}
+
+func (s *Struct) Type() ObjectType { return STRUCT_OBJ }
+func (s *Struct) Inspect() string  { return inspect(s, map[Object]bool{}) }
+
+// Structural hash of the definition name and field values, consistent with Equal
+func (s *Struct) HashKey() HashKey {
+	return structuralHashKey(s, map[Object]int{})
+}
+
+// Checks equality of keys whose HashKeys are identical
+func keysEqual(a Object, b Object) bool {
+	return Equal(a, b)
+}
+
+// Value equality(=*=): containers are compared element by element, hashes regardless of insertion order
+func Equal(a Object, b Object) bool {
+	return equal(a, b, map[Object]int{}, map[Object]int{})
+}
+
+// Containers being compared are tracked with their depth. A container reached again inside itself(a cycle)
+// equals only a cycle of the other value back to the container at the same depth
+func equal(a Object, b Object, visitingA map[Object]int, visitingB map[Object]int) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+	depthA, cycleA := visitingA[a]
+	depthB, cycleB := visitingB[b]
+	if cycleA || cycleB {
+		return cycleA && cycleB && depthA == depthB
+	}
+	switch a.(type) {
+	case *Array, *Hash, *Set, *Struct:
+		visitingA[a], visitingB[b] = len(visitingA), len(visitingB)
+		defer delete(visitingA, a)
+		defer delete(visitingB, b)
 	}
-	out.WriteString("{")
-	out.WriteString(strings.Join(pairsMsg, ", "))
-	out.WriteString("}")
-	return out.String()
 
+	switch a := a.(type) {
+	case *Integer:
+		return a.Value == b.(*Integer).Value
+	case *Boolean:
+		return a.Value == b.(*Boolean).Value
+	case *String:
+		return a.Value == b.(*String).Value
+	case *Array:
+		other := b.(*Array)
+		if len(a.Elements) != len(other.Elements) {
+			return false
+		}
+		for i, e := range a.Elements {
+			if !equal(e, other.Elements[i], visitingA, visitingB) {
+				return false
+			}
+		}
+		return true
+	case *Hash:
+		other := b.(*Hash)
+		if a.Len() != other.Len() {
+			return false
+		}
+		for _, entry := range a.Order {
+			key, ok := entry.Key.(Hashable)
+			if !ok {
+				return false
+			}
+			value, found := other.Get(key)
+			if !found || !equal(entry.Value, value, visitingA, visitingB) {
+				return false
+			}
+		}
+		return true
+	case *Set:
+		other := b.(*Set)
+		if a.Len() != other.Len() {
+			return false
+		}
+		for _, member := range a.Elements() {
+			if !other.Has(member.(Hashable)) {
+				return false
+			}
+		}
+		return true
+	case *Struct:
+		other := b.(*Struct)
+		if a.Definition != other.Definition {
+			return false
+		}
+		for i, v := range a.Values {
+			if !equal(v, other.Values[i], visitingA, visitingB) {
+				return false
+			}
+		}
+		return true
+	case *StructDefinition:
+		return a == b
+	default:
+		return a.Inspect() == b.Inspect()
+	}
+}
+
+// Hashes containers by their contents, tracking the containers being hashed with their depth like equal.
+// A container reached again inside itself(a cycle) is hashed by that depth instead of its contents again
+func structuralHashKey(obj Object, visiting map[Object]int) HashKey {
+	if depth, cycle := visiting[obj]; cycle {
+		return HashKey{Type: obj.Type(), Value: uint64(depth)}
+	}
+	switch obj.(type) {
+	case *Array, *Hash, *Set, *Struct:
+		visiting[obj] = len(visiting)
+		defer delete(visiting, obj)
+	}
+
+	switch obj := obj.(type) {
+	case *Array:
+		hash := fnv.New64a()
+		for _, e := range obj.Elements {
+			writeHashKey(hash, structuralHashKey(e, visiting))
+		}
+		return HashKey{Type: obj.Type(), Value: hash.Sum64()}
+	case *Hash:
+		var sum uint64
+		for _, entry := range obj.Order {
+			hash := fnv.New64a()
+			writeHashKey(hash, structuralHashKey(entry.Key, visiting))
+			writeHashKey(hash, structuralHashKey(entry.Value, visiting))
+			sum += hash.Sum64()
+		}
+		return HashKey{Type: obj.Type(), Value: sum}
+	case *Set:
+		return HashKey{Type: obj.Type(), Value: structuralHashKey(obj.Members, visiting).Value}
+	case *Struct:
+		hash := fnv.New64a()
+		hash.Write([]byte(obj.Definition.Name))
+		for _, v := range obj.Values {
+			writeHashKey(hash, structuralHashKey(v, visiting))
+		}
+		return HashKey{Type: obj.Type(), Value: hash.Sum64()}
+	default:
+		return hashKeyOf(obj)
+	}
+}
+
+// HashKey of any object, unhashable objects are hashed by their inspected value
+func hashKeyOf(obj Object) HashKey {
+	if hashable, ok := obj.(Hashable); ok {
+		return hashable.HashKey()
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(obj.Inspect()))
+	return HashKey{Type: obj.Type(), Value: hash.Sum64()}
+}
+
+func writeHashKey(h hash.Hash64, key HashKey) {
+	h.Write([]byte(key.Type))
+	binary.Write(h, binary.LittleEndian, key.Value)
+}
+
+// Copies containers used as keys into frozen snapshots, and scalars into new values, so later changes to the
+// original cannot alter the key. Keys that are already frozen are used as is
+func snapshotKey(key Hashable) Hashable {
+	switch key := key.(type) {
+	case *Integer:
+		return &Integer{Value: key.Value}
+	case *String:
+		return &String{Value: key.Value}
+	case *Array, *Hash, *Set, *Struct:
+		if IsFrozen(key) {
+			return key
+		}
+		return Freeze(DeepCopy(key)).(Hashable)
+	default:
+		return key
+	}
+}
+
+// Makes containers and everything they contain immutable
+func Freeze(obj Object) Object {
+	if IsFrozen(obj) { // Contents of frozen containers are already frozen
+		return obj
+	}
+	switch obj := obj.(type) {
+	case *Array:
+		obj.Frozen = true
+		for _, e := range obj.Elements {
+			Freeze(e)
+		}
+	case *Hash:
+		obj.Frozen = true
+		for _, entry := range obj.Order {
+			Freeze(entry.Key)
+			Freeze(entry.Value)
+		}
+	case *Set:
+		obj.Frozen = true
+		Freeze(obj.Members)
+	case *Struct:
+		obj.Frozen = true
+		for _, v := range obj.Values {
+			Freeze(v)
+		}
+	}
+	return obj
+}
+
+// Returns a copy of obj sharing nothing mutable with it, cycles in obj are reproduced in the copy
+// Frozen containers are immutable and therefore shared rather than copied
+func DeepCopy(obj Object) Object {
+	return deepCopy(obj, map[Object]Object{})
+}
+
+func deepCopy(obj Object, copies map[Object]Object) Object {
+	if copied, ok := copies[obj]; ok {
+		return copied
+	}
+	if IsFrozen(obj) {
+		return obj
+	}
+	switch obj := obj.(type) {
+	case *Integer:
+		return &Integer{Value: obj.Value}
+	case *String:
+		return &String{Value: obj.Value}
+	case *Array:
+		copied := &Array{Elements: make([]Object, len(obj.Elements))}
+		copies[obj] = copied
+		for i, e := range obj.Elements {
+			copied.Elements[i] = deepCopy(e, copies)
+		}
+		return copied
+	case *Hash:
+		copied := NewHash()
+		copies[obj] = copied
+		for _, entry := range obj.Order { // Set snapshots the keys again
+			copied.Set(entry.Key.(Hashable), deepCopy(entry.Value, copies))
+		}
+		return copied
+	case *Set:
+		copied := NewSet()
+		copies[obj] = copied
+		for _, member := range obj.Elements() { // Add snapshots the members again
+			copied.Add(member.(Hashable))
+		}
+		return copied
+	case *Struct:
+		copied := NewStruct(obj.Definition, make([]Object, len(obj.Values)))
+		copies[obj] = copied
+		for i, v := range obj.Values {
+			copied.Values[i] = deepCopy(v, copies)
+		}
+		return copied
+	default: // Booleans, null and functions are immutable
+		return obj
+	}
+}
+
+// Returns true if obj is a frozen container
+func IsFrozen(obj Object) bool {
+	switch obj := obj.(type) {
+	case *Array:
+		return obj.Frozen
+	case *Hash:
+		return obj.Frozen
+	case *Set:
+		return obj.Frozen
+	case *Struct:
+		return obj.Frozen
+	default:
+		return false
+	}
 }
 
 type Null struct{}
@@ -138,6 +623,7 @@ func (rv *ReturnValue) Type() ObjectType { return RETURN_VAL_OBJ }
 func (rv *ReturnValue) Inspect() string  { return rv.Value.Inspect() }
 
 type Function struct {
+	Name       string // Empty for anonymous functions
 	Parameters []*ast.Identifier
 	Body       *ast.BlockStatement
 	Env        *Environment
@@ -153,6 +639,9 @@ func (fun *Function) Inspect() string {
 	}
 
 	out.WriteString("fun")
+	if fun.Name != "" {
+		out.WriteString(" " + fun.Name)
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(") {\n")
@@ -171,7 +660,16 @@ func (b *BuiltIn) Inspect() string  { return "BuiltIn Function" }
 
 type Error struct {
 	Message string
+	Value   Object   // Value passed to throw, nil for runtime errors
+	Stack   []string // Names of the functions the error unwound through, innermost first
 }
 
 func (err *Error) Type() ObjectType { return ERROR_OBJ }
-func (err *Error) Inspect() string  { return err.Message }
+func (err *Error) Inspect() string {
+	var out bytes.Buffer
+	out.WriteString(err.Message)
+	for _, frame := range err.Stack {
+		out.WriteString("\n\tat " + frame)
+	}
+	return out.String()
+}
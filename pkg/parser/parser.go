@@ -12,6 +12,8 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	PIPE        // |>
+	COALESCE    // ??
 	ANDOR       // & or |
 	EQUALS      // ==
 	LESSGREATER // < or >
@@ -24,6 +26,8 @@ const (
 )
 
 var precedences = map[token.TokenType]int{
+	token.PIPE:          PIPE,
+	token.COALESCE:      COALESCE,
 	token.AND:           ANDOR,
 	token.OR:            ANDOR,
 	token.REF_EQUALS:    EQUALS,
@@ -41,6 +45,7 @@ var precedences = map[token.TokenType]int{
 	token.LPAREN:        FUNCALL,
 	token.LBRACKET:      INDEX,
 	token.DOT:           DOT,
+	token.OPTIONAL_DOT:  DOT,
 }
 
 type Parser struct {
@@ -66,12 +71,15 @@ func New(tokenizer *tokenizer.Tokenizer) *Parser {
 	p.addPrefix(token.IDENTIF, p.parseIdentifier)
 	p.addPrefix(token.INT, p.parseIntegerLiteral)
 	p.addPrefix(token.STRING, p.parseStringLiteral)
+	p.addPrefix(token.TEMPLATE, p.parseTemplateLiteral)
 	p.addPrefix(token.TRUE, p.parseBoolean)
 	p.addPrefix(token.FALSE, p.parseBoolean)
 	p.addPrefix(token.LPAREN, p.parseGroupExpression)
 	p.addPrefix(token.LBRACKET, p.parseArrayLiteral)
 	p.addPrefix(token.LBRACE, p.parseHashLiteral)
+	p.addPrefix(token.SET_LBRACE, p.parseSetLiteral)
 	p.addPrefix(token.IF, p.parseIfExpression)
+	p.addPrefix(token.TRY, p.parseTryExpression)
 	p.addPrefix(token.WHILE, p.parseWhileExpression)
 	p.addPrefix(token.FUNCTION, p.parseFunctionLiteral)
 	p.addPrefix(token.NOT, p.parsePrefixOperationExpression)
@@ -94,7 +102,10 @@ func New(tokenizer *tokenizer.Tokenizer) *Parser {
 	p.addInfix(token.GREATERTHAN, p.parseInfixExpression)
 	p.addInfix(token.LBRACKET, p.parseIndexExpression)
 	p.addInfix(token.DOT, p.parseDotExpression)
+	p.addInfix(token.OPTIONAL_DOT, p.parseDotExpression)
+	p.addInfix(token.COALESCE, p.parseInfixExpression)
 	p.addInfix(token.LPAREN, p.parseCallExpression)
+	p.addInfix(token.PIPE, p.parsePipeExpression)
 
 	// Read tokens in pairs, so currToken and peekToken are both set
 	p.nextToken()
@@ -230,6 +241,16 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expression
 }
 
+func (p *Parser) parsePipeExpression(left ast.Expression) ast.Expression {
+	expression := &ast.PipeExpression{Token: p.currentToken, Left: left}
+
+	precedence := p.currPrecedence()
+	p.nextToken()
+	expression.Right = p.parseExpression(precedence)
+
+	return expression
+}
+
 /* SEMANTIC CODE FUNTIONS */
 
 func (p *Parser) parseIdentifier() ast.Expression {
@@ -241,6 +262,10 @@ func (p *Parser) parseBoolean() ast.Expression {
 }
 
 func (p *Parser) parseGroupExpression() ast.Expression {
+	if p.isArrowFunction() {
+		return p.parseArrowFunction()
+	}
+
 	p.nextToken()
 
 	exp := p.parseExpression(LOWEST)
@@ -252,6 +277,51 @@ func (p *Parser) parseGroupExpression() ast.Expression {
 	return exp
 }
 
+// Looks ahead for "(<comma separated identifiers>) =>" without consuming any tokens
+// Tells arrow functions apart from grouped expressions, current token is LPAREN
+func (p *Parser) isArrowFunction() bool {
+	lookahead := *p.tokenizer // Copy, leaving the parser's tokenizer untouched
+	tok := p.peekToken
+	depth := 0 // Nesting of the brackets of annotations in the parameters
+	for tok.Type != token.EOF {
+		switch tok.Type {
+		case token.LPAREN, token.LBRACKET, token.LBRACE, token.SET_LBRACE:
+			depth++
+		case token.RBRACKET, token.RBRACE:
+			depth--
+		case token.RPAREN:
+			if depth == 0 {
+				return lookahead.NextToken().Type == token.ARROW
+			}
+			depth--
+		}
+		tok = lookahead.NextToken()
+	}
+	return false
+}
+
+// ARROW FUNCTION -> "(<comma separated identifiers>) => <expression>"
+// Lowered to a function literal returning the expression
+func (p *Parser) parseArrowFunction() ast.Expression {
+	funcExp := &ast.FunctionLiteral{Token: token.Token{Type: token.FUNCTION, Literal: "fun"}}
+	funcExp.Parameters = p.parseParameters()
+
+	if !p.peekNextToken(token.ARROW, true) {
+		return nil
+	}
+
+	body := &ast.BlockStatement{Token: p.currentToken}
+	ret := &ast.ReturnStatement{Token: token.Token{Type: token.RETURN, Literal: "return"}}
+
+	p.nextToken()
+	ret.ReturnValue = p.parseExpression(LOWEST)
+
+	body.Statements = []ast.Statement{ret}
+	funcExp.Body = body
+
+	return funcExp
+}
+
 func (p *Parser) parseWhileExpression() ast.Expression {
 	expression := &ast.WhileExpression{Token: p.currentToken}
 
@@ -309,6 +379,40 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseTryExpression() ast.Expression {
+	expression := &ast.TryExpression{Token: p.currentToken}
+
+	if !p.peekNextToken(token.LBRACE, true) {
+		return nil
+	}
+	expression.Block = p.parseBlockStatement()
+
+	if p.peekNextToken(token.CATCH, false) {
+		if !p.peekNextToken(token.LPAREN, true) || !p.peekNextToken(token.IDENTIF, true) {
+			return nil
+		}
+		expression.Param = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+		if !p.peekNextToken(token.RPAREN, true) || !p.peekNextToken(token.LBRACE, true) {
+			return nil
+		}
+		expression.Catch = p.parseBlockStatement()
+	}
+
+	if p.peekNextToken(token.FINALLY, false) {
+		if !p.peekNextToken(token.LBRACE, true) {
+			return nil
+		}
+		expression.Finally = p.parseBlockStatement()
+	}
+
+	if expression.Catch == nil && expression.Finally == nil {
+		p.peekNextTokenError(token.CATCH, token.FINALLY)
+		return nil
+	}
+
+	return expression
+}
+
 func (p *Parser) parseArrayLiteral() ast.Expression {
 	arr := &ast.ArrayLiteral{Token: p.currentToken} // token.LBRACKET
 	elems := []ast.Expression{}
@@ -337,9 +441,11 @@ func (p *Parser) parseArrayLiteral() ast.Expression {
 func (p *Parser) parseHashLiteral() ast.Expression {
 	hash := &ast.HashLiteral{Token: p.currentToken} // token.LBRACE
 	pairs := map[ast.Expression]ast.Expression{}
+	keys := []ast.Expression{}
 
 	if p.peekNextToken(token.RBRACE, false) {
 		hash.Pairs = pairs
+		hash.Keys = keys
 		return hash
 	}
 
@@ -352,6 +458,7 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 		p.nextToken()
 		val := p.parseExpression(LOWEST)
 		pairs[key] = val
+		keys = append(keys, key)
 
 		if !p.checkIdNextToken(token.RBRACE) && !p.peekNextToken(token.COMMA, true) {
 			return nil
@@ -363,9 +470,35 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 	}
 
 	hash.Pairs = pairs
+	hash.Keys = keys
 	return hash
 }
 
+func (p *Parser) parseSetLiteral() ast.Expression {
+	set := &ast.SetLiteral{Token: p.currentToken} // token.SET_LBRACE
+	elems := []ast.Expression{}
+
+	if p.peekNextToken(token.RBRACE, false) {
+		set.Elements = elems
+		return set
+	}
+
+	p.nextToken()
+	elems = append(elems, p.parseExpression(LOWEST))
+
+	for p.peekNextToken(token.COMMA, false) {
+		p.nextToken()
+		elems = append(elems, p.parseExpression(LOWEST))
+	}
+
+	if !p.peekNextToken(token.RBRACE, true) {
+		return nil
+	}
+
+	set.Elements = elems
+	return set
+}
+
 func (p *Parser) parseFunctionLiteral() ast.Expression {
 	funcExp := &ast.FunctionLiteral{Token: p.currentToken}
 
@@ -375,6 +508,14 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 
 	funcExp.Parameters = p.parseParameters()
 
+	if p.peekNextToken(token.THIN_ARROW, false) {
+		p.nextToken()
+		funcExp.ReturnType = p.parseTypeAnnotation()
+		if funcExp.ReturnType == nil {
+			return nil
+		}
+	}
+
 	if !p.peekNextToken(token.LBRACE, true) {
 		return nil
 	}
@@ -392,37 +533,146 @@ func (p *Parser) parseParameters() []*ast.Identifier {
 		return parameters
 	}
 
-	for !p.checkIdCurrentToken(token.RPAREN) {
-		if p.peekNextToken(token.IDENTIF, false) {
-			identifier := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
-			parameters = append(parameters, identifier)
-			p.peekNextToken(token.COMMA, false)
+	for {
+		if !p.peekNextToken(token.IDENTIF, true) {
+			return nil
+		}
+		identifier := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+		if p.peekNextToken(token.COLON, false) {
+			p.nextToken()
+			identifier.Annotation = p.parseTypeAnnotation()
+			if identifier.Annotation == nil {
+				return nil
+			}
+		}
+		parameters = append(parameters, identifier)
+		if !p.peekNextToken(token.COMMA, false) {
+			break
 		}
-		p.peekNextToken(token.RPAREN, false)
+	}
+
+	if !p.peekNextToken(token.RPAREN, true) {
+		return nil
 	}
 
 	return parameters
 }
 
+// TYPE ANNOTATION -> <name> | [<type>] | {<type>: <type>} | #{<type>} | fun(<types>) -> <type>
+func (p *Parser) parseTypeAnnotation() ast.TypeAnnotation {
+	switch p.currentToken.Type {
+	case token.IDENTIF:
+		return &ast.NamedType{Token: p.currentToken, Name: p.currentToken.Literal}
+	case token.LBRACKET:
+		arrType := &ast.ArrayType{Token: p.currentToken}
+		p.nextToken()
+		if arrType.Element = p.parseTypeAnnotation(); arrType.Element == nil {
+			return nil
+		}
+		if !p.peekNextToken(token.RBRACKET, true) {
+			return nil
+		}
+		return arrType
+	case token.LBRACE:
+		hashType := &ast.HashType{Token: p.currentToken}
+		p.nextToken()
+		if hashType.Key = p.parseTypeAnnotation(); hashType.Key == nil {
+			return nil
+		}
+		if !p.peekNextToken(token.COLON, true) {
+			return nil
+		}
+		p.nextToken()
+		if hashType.Value = p.parseTypeAnnotation(); hashType.Value == nil {
+			return nil
+		}
+		if !p.peekNextToken(token.RBRACE, true) {
+			return nil
+		}
+		return hashType
+	case token.SET_LBRACE:
+		setType := &ast.SetType{Token: p.currentToken}
+		p.nextToken()
+		if setType.Element = p.parseTypeAnnotation(); setType.Element == nil {
+			return nil
+		}
+		if !p.peekNextToken(token.RBRACE, true) {
+			return nil
+		}
+		return setType
+	case token.FUNCTION:
+		funcType := &ast.FunctionType{Token: p.currentToken, Parameters: []ast.TypeAnnotation{}}
+		if !p.peekNextToken(token.LPAREN, true) {
+			return nil
+		}
+		if !p.peekNextToken(token.RPAREN, false) {
+			for {
+				p.nextToken()
+				param := p.parseTypeAnnotation()
+				if param == nil {
+					return nil
+				}
+				funcType.Parameters = append(funcType.Parameters, param)
+				if !p.peekNextToken(token.COMMA, false) {
+					break
+				}
+			}
+			if !p.peekNextToken(token.RPAREN, true) {
+				return nil
+			}
+		}
+		if !p.peekNextToken(token.THIN_ARROW, true) {
+			return nil
+		}
+		p.nextToken()
+		if funcType.Return = p.parseTypeAnnotation(); funcType.Return == nil {
+			return nil
+		}
+		return funcType
+	default:
+		msg := fmt.Sprintf("expected type annotation, but got %s", p.currentToken.Type)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+}
+
 func (p *Parser) parseIndexExpression(array ast.Expression) ast.Expression {
-	idxExp := &ast.IndexExpression{Token: p.currentToken, Left: array}
+	bracket := p.currentToken
 
 	p.nextToken()
 
-	idxExp.Index = p.parseExpression(LOWEST)
+	var start ast.Expression
+	if !p.checkIdCurrentToken(token.COLON) {
+		start = p.parseExpression(LOWEST)
+		if !p.peekNextToken(token.COLON, false) {
+			if !p.peekNextToken(token.RBRACKET, true) {
+				return nil
+			}
+			return &ast.IndexExpression{Token: bracket, Left: array, Index: start}
+		}
+	}
+
+	// Slice form, current token is the colon
+	sliceExp := &ast.SliceExpression{Token: bracket, Left: array, Start: start}
+	if p.peekNextToken(token.RBRACKET, false) {
+		return sliceExp
+	}
+
+	p.nextToken()
+	sliceExp.End = p.parseExpression(LOWEST)
 
 	if !p.peekNextToken(token.RBRACKET, true) {
 		return nil
 	}
 
-	return idxExp
+	return sliceExp
 }
 
 func (p *Parser) parseDotExpression(hash ast.Expression) ast.Expression {
 	dotExp := &ast.DotExpression{Token: p.currentToken, Left: hash}
 
 	p.nextToken()
-	dotExp.Attribute = p.parseExpression(LOWEST)
+	dotExp.Attribute = p.parseExpression(DOT)
 
 	return dotExp
 }
@@ -468,19 +718,77 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 }
 
 func (p *Parser) parseStringLiteral() ast.Expression {
-	lit := &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal}
+	lit := &ast.StringLiteral{Token: p.currentToken, Value: tokenizer.Unescape(p.currentToken.Literal)}
 	return lit
 }
 
+func (p *Parser) parseTemplateLiteral() ast.Expression {
+	template := &ast.TemplateLiteral{Token: p.currentToken}
+
+	texts, exprs, ok := tokenizer.SplitTemplate(p.currentToken.Literal)
+	if !ok {
+		msg := fmt.Sprintf("Unterminated interpolation in string %q", p.currentToken.Literal)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
+	for i, text := range texts {
+		if text != "" {
+			template.Parts = append(template.Parts, &ast.StringLiteral{Token: token.Token{Type: token.STRING, Literal: text}, Value: tokenizer.Unescape(text)})
+		}
+		if i == len(exprs) {
+			break
+		}
+		expr := p.parseInterpolation(exprs[i])
+		if expr == nil {
+			return nil
+		}
+		template.Parts = append(template.Parts, expr)
+	}
+
+	return template
+}
+
+// Parses the source of an interpolated expression with its own parser, reporting its errors
+func (p *Parser) parseInterpolation(source string) ast.Expression {
+	inner := New(tokenizer.New(source))
+	var expr ast.Expression
+	if !inner.checkIdCurrentToken(token.EOF) {
+		expr = inner.parseExpression(LOWEST)
+		inner.peekNextToken(token.EOF, true)
+	} else {
+		inner.errors = append(inner.errors, "Empty interpolation in string")
+	}
+	for _, msg := range inner.errors {
+		p.errors = append(p.errors, fmt.Sprintf("In interpolation ${%s}: %s", source, msg))
+	}
+	if len(inner.errors) > 0 {
+		return nil
+	}
+	return expr
+}
+
 /* STATEMENT PARSING */
 
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.currentToken.Type {
-	case token.LET:
+	case token.LET, token.CONST:
 		return p.parseAssignmentStatement(false)
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
+	case token.STRUCT:
+		return p.parseStructDefinition()
+	case token.FUNCTION: // named function declarations
+		if p.checkIdNextToken(token.IDENTIF) {
+			return p.parseFunctionDeclaration()
+		}
+		return p.parseExpressionStatement()
 	case token.IDENTIF: // re-assignment statements
+		if p.currentToken.Literal == "type" && p.checkIdNextToken(token.IDENTIF) { // "type" stays usable as an identifier
+			return p.parseTypeDefinition()
+		}
 		// TODO: checkIdNextToken should be a variadic function for cleaner code
 		if p.checkIdNextToken(token.ASSIGN) || p.checkIdNextToken(token.REF_ASSIGN) || p.checkIdNextToken(token.VAL_ASSIGN) {
 			return p.parseAssignmentStatement(true)
@@ -494,9 +802,8 @@ func (p *Parser) parseStatement() ast.Statement {
 
 func (p *Parser) parseAssignmentStatement(reassignmentFlag bool) *ast.AssignmentStatement {
 	assStatement := &ast.AssignmentStatement{}
-	assStatement.Token = token.Token{Type: token.LET, Literal: "let"}
+	assStatement.Token = p.currentToken // Going to be LET or CONST type, IDENTIF type for re-assignments
 	if !reassignmentFlag {
-		assStatement.Token = p.currentToken // Going to be LET type
 		if !p.peekNextToken(token.IDENTIF, true) {
 			return nil
 		}
@@ -505,6 +812,14 @@ func (p *Parser) parseAssignmentStatement(reassignmentFlag bool) *ast.Assignment
 	// There is an Identifier i.e is of form '''let <identifier> <...>'''
 	assStatement.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
 
+	if !reassignmentFlag && p.peekNextToken(token.COLON, false) {
+		p.nextToken()
+		assStatement.Name.Annotation = p.parseTypeAnnotation()
+		if assStatement.Name.Annotation == nil {
+			return nil
+		}
+	}
+
 	assignmentFlag := false
 	if p.peekNextToken(token.ASSIGN, false) || p.peekNextToken(token.REF_ASSIGN, false) || p.peekNextToken(token.VAL_ASSIGN, false) {
 		assStatement.AssignmentOperator = p.currentToken
@@ -528,6 +843,28 @@ func (p *Parser) parseAssignmentStatement(reassignmentFlag bool) *ast.Assignment
 	return assStatement
 }
 
+func (p *Parser) parseFunctionDeclaration() ast.Statement {
+	funcDecl := &ast.FunctionDeclaration{Token: p.currentToken} // Going to be FUNCTION type
+
+	p.nextToken()
+	funcDecl.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	// Parses '''<identifier>(<parameters>) <body>''' as a function literal
+	funcExp, ok := p.parseFunctionLiteral().(*ast.FunctionLiteral)
+	if !ok {
+		return nil
+	}
+	funcExp.Token = funcDecl.Token
+	funcExp.Name = funcDecl.Name.Value
+	funcDecl.Function = funcExp
+
+	if p.checkIdNextToken(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return funcDecl
+}
+
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	retStatement := &ast.ReturnStatement{Token: p.currentToken} // Going to be RETURN type
 
@@ -542,6 +879,23 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return retStatement
 }
 
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	throwStatement := &ast.ThrowStatement{Token: p.currentToken}
+
+	p.nextToken()
+
+	throwStatement.Value = p.parseExpression(LOWEST)
+	if throwStatement.Value == nil {
+		return nil
+	}
+
+	if p.checkIdNextToken(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return throwStatement
+}
+
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	blockStmt := &ast.BlockStatement{Token: p.currentToken}
 	blockStmt.Statements = []ast.Statement{}
@@ -559,10 +913,17 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	return blockStmt
 }
 
-func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
+func (p *Parser) parseExpressionStatement() ast.Statement {
 	expStatement := &ast.ExpressionStatement{Token: p.currentToken}
 	expStatement.Expression = p.parseExpression(LOWEST)
 
+	// Its of form '''<expression>.<identifier> = <...>'''
+	if target, ok := expStatement.Expression.(*ast.DotExpression); ok {
+		if p.peekNextToken(token.ASSIGN, false) || p.peekNextToken(token.REF_ASSIGN, false) || p.peekNextToken(token.VAL_ASSIGN, false) {
+			return p.parseFieldAssignmentStatement(target)
+		}
+	}
+
 	if p.checkIdNextToken(token.SEMICOLON) {
 		p.nextToken()
 	}
@@ -570,6 +931,124 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	return expStatement
 }
 
+func (p *Parser) parseFieldAssignmentStatement(target *ast.DotExpression) ast.Statement {
+	fieldStatement := &ast.FieldAssignmentStatement{Token: p.currentToken, Target: target}
+
+	if target.Token.Type == token.OPTIONAL_DOT {
+		msg := fmt.Sprintf("cannot assign to optional field %s", target.String())
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+	if _, ok := target.Attribute.(*ast.Identifier); !ok {
+		msg := fmt.Sprintf("expected field name to assign, but got %s", target.Attribute.String())
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
+	p.nextToken()
+	fieldStatement.Value = p.parseExpression(LOWEST)
+
+	if p.checkIdNextToken(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return fieldStatement
+}
+
+func (p *Parser) parseStructDefinition() ast.Statement {
+	structDef := &ast.StructDefinition{Token: p.currentToken, Fields: []*ast.Identifier{}}
+
+	if !p.peekNextToken(token.IDENTIF, true) {
+		return nil
+	}
+	structDef.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	if !p.peekNextToken(token.LBRACE, true) {
+		return nil
+	}
+
+	for !p.peekNextToken(token.RBRACE, false) {
+		if !p.peekNextToken(token.IDENTIF, true) {
+			return nil
+		}
+		field := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+		for _, declared := range structDef.Fields {
+			if declared.Value == field.Value {
+				msg := fmt.Sprintf("duplicate field %s in struct %s", field.Value, structDef.Name.Value)
+				p.errors = append(p.errors, msg)
+				return nil
+			}
+		}
+		if p.peekNextToken(token.COLON, false) {
+			p.nextToken()
+			if field.Annotation = p.parseTypeAnnotation(); field.Annotation == nil {
+				return nil
+			}
+		}
+		structDef.Fields = append(structDef.Fields, field)
+
+		if !p.checkIdNextToken(token.RBRACE) && !p.peekNextToken(token.COMMA, true) {
+			return nil
+		}
+	}
+
+	if p.checkIdNextToken(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return structDef
+}
+
+func (p *Parser) parseTypeDefinition() ast.Statement {
+	typeDef := &ast.TypeDefinition{Token: p.currentToken, Variants: []*ast.Variant{}}
+
+	p.nextToken()
+	typeDef.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	if !p.peekNextToken(token.ASSIGN, true) {
+		return nil
+	}
+
+	for {
+		if !p.peekNextToken(token.IDENTIF, true) {
+			return nil
+		}
+		variant := &ast.Variant{Name: &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}, Fields: []*ast.Identifier{}}
+		for _, declared := range typeDef.Variants {
+			if declared.Name.Value == variant.Name.Value {
+				msg := fmt.Sprintf("duplicate variant %s in type %s", variant.Name.Value, typeDef.Name.Value)
+				p.errors = append(p.errors, msg)
+				return nil
+			}
+		}
+		if p.peekNextToken(token.LPAREN, false) {
+			if variant.Fields = p.parseParameters(); variant.Fields == nil {
+				return nil
+			}
+			for i, field := range variant.Fields {
+				for _, declared := range variant.Fields[:i] {
+					if declared.Value == field.Value {
+						msg := fmt.Sprintf("duplicate field %s in variant %s", field.Value, variant.Name.Value)
+						p.errors = append(p.errors, msg)
+						return nil
+					}
+				}
+			}
+		}
+		typeDef.Variants = append(typeDef.Variants, variant)
+
+		if !p.peekNextToken(token.OR, false) {
+			break
+		}
+	}
+
+	if p.checkIdNextToken(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return typeDef
+}
+
 func (p *Parser) ParseProgram() *ast.Program {
 	program := &ast.Program{}
 	program.Statements = []ast.Statement{}
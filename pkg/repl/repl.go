@@ -11,6 +11,7 @@ import (
 	"github.com/Youssef-Mak/baby-interpreter/pkg/object"
 	"github.com/Youssef-Mak/baby-interpreter/pkg/parser"
 	"github.com/Youssef-Mak/baby-interpreter/pkg/tokenizer"
+	"github.com/Youssef-Mak/baby-interpreter/pkg/typecheck"
 )
 
 const PROMPT = ">> "
@@ -18,6 +19,7 @@ const PROMPT = ">> "
 func Initialize(in io.Reader, out io.Writer) {
 	scanner := bufio.NewScanner(in)
 	env := object.NewEnvironment()
+	checker := typecheck.NewSession()
 
 	for {
 		fmt.Printf(PROMPT)
@@ -36,14 +38,14 @@ func Initialize(in io.Reader, out io.Writer) {
 				contents := string(buf)
 				io.WriteString(out, contents)
 				io.WriteString(out, "\n")
-				evaluated, ok = InterpretInput(contents, out, env)
+				evaluated, ok = InterpretInput(contents, out, env, checker)
 			} else {
 				io.WriteString(out, fmt.Sprintf("Error reading Baby File: %s", err.Error()))
 				io.WriteString(out, "\n")
 				continue
 			}
 		} else {
-			evaluated, ok = InterpretInput(line, out, env)
+			evaluated, ok = InterpretInput(line, out, env, checker)
 		}
 
 		if !ok {
@@ -56,7 +58,7 @@ func Initialize(in io.Reader, out io.Writer) {
 	}
 }
 
-func InterpretInput(input string, out io.Writer, env *object.Environment) (object.Object, bool) {
+func InterpretInput(input string, out io.Writer, env *object.Environment, checker *typecheck.Session) (object.Object, bool) {
 	tokenizer := tokenizer.New(input)
 	parser := parser.New(tokenizer)
 
@@ -66,6 +68,11 @@ func InterpretInput(input string, out io.Writer, env *object.Environment) (objec
 		return nil, false
 	}
 
+	if diagnostics := checker.Check(program); len(diagnostics) != 0 {
+		printParserErrors(out, diagnostics)
+		return nil, false
+	}
+
 	evaluated := evaluator.Eval(program, env)
 	return evaluated, true
 }
@@ -7,9 +7,10 @@ const (
 	EOF     TokenType = "EOF"
 
 	// Identifiers + literals
-	IDENTIF TokenType = "IDENTIF" // add, foobar, x, y, ...
-	INT     TokenType = "INT"     // 1343456
-	STRING  TokenType = "STRING"  // "Hello World"
+	IDENTIF  TokenType = "IDENTIF"  // add, foobar, x, y, ...
+	INT      TokenType = "INT"      // 1343456
+	STRING   TokenType = "STRING"   // "Hello World"
+	TEMPLATE TokenType = "TEMPLATE" // "Hello ${name}"
 	// Operators
 	ASSIGN     TokenType = "="
 	REF_ASSIGN TokenType = "=&"
@@ -20,6 +21,12 @@ const (
 	ASTERIX    TokenType = "*"
 	NOT        TokenType = "!"
 	DOT        TokenType = "."
+	ARROW      TokenType = "=>"
+	PIPE       TokenType = "|>"
+	THIN_ARROW TokenType = "->"
+	// Null safety
+	OPTIONAL_DOT TokenType = "?."
+	COALESCE     TokenType = "??"
 	// Logic
 	LESSTHAN      TokenType = "<"
 	GREATERTHAN   TokenType = ">"
@@ -34,22 +41,29 @@ const (
 	TRUE          TokenType = "TRUE"
 	FALSE         TokenType = "FALSE"
 	// Delimiters
-	COMMA     TokenType = ","
-	SEMICOLON TokenType = ";"
-	COLON     TokenType = ";"
-	LPAREN    TokenType = "("
-	RPAREN    TokenType = ")"
-	LBRACE    TokenType = "{"
-	RBRACE    TokenType = "}"
-	LBRACKET  TokenType = "["
-	RBRACKET  TokenType = "]"
+	COMMA      TokenType = ","
+	SEMICOLON  TokenType = ";"
+	COLON      TokenType = ":"
+	LPAREN     TokenType = "("
+	RPAREN     TokenType = ")"
+	LBRACE     TokenType = "{"
+	RBRACE     TokenType = "}"
+	LBRACKET   TokenType = "["
+	RBRACKET   TokenType = "]"
+	SET_LBRACE TokenType = "#{"
 	// Keywords
 	FUNCTION TokenType = "FUNCTION"
 	LET      TokenType = "LET"
+	CONST    TokenType = "CONST"
 	IF       TokenType = "IF"
 	ELSE     TokenType = "ELSE"
 	WHILE    TokenType = "WHILE"
 	RETURN   TokenType = "return"
+	STRUCT   TokenType = "STRUCT"
+	THROW    TokenType = "THROW"
+	TRY      TokenType = "TRY"
+	CATCH    TokenType = "CATCH"
+	FINALLY  TokenType = "FINALLY"
 )
 
 type Token struct {
@@ -63,12 +77,18 @@ var valMap = map[string]TokenType{
 }
 
 var keywordMap = map[string]TokenType{
-	"fun":    FUNCTION,
-	"let":    LET,
-	"if":     IF,
-	"else":   ELSE,
-	"while":  WHILE,
-	"return": RETURN,
+	"fun":     FUNCTION,
+	"let":     LET,
+	"const":   CONST,
+	"if":      IF,
+	"else":    ELSE,
+	"while":   WHILE,
+	"return":  RETURN,
+	"struct":  STRUCT,
+	"throw":   THROW,
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
 }
 
 func IdentLookUp(id string) TokenType {
@@ -2,6 +2,7 @@ package tokenizer
 
 import (
 	"regexp"
+	"strings"
 
 	"github.com/Youssef-Mak/baby-interpreter/pkg/token"
 )
@@ -28,7 +29,12 @@ func (t *Tokenizer) NextToken() token.Token {
 	case '+':
 		tok = newToken(token.PLUS, t.ch)
 	case '-':
-		tok = newToken(token.MINUS, t.ch)
+		if t.peekChar() == '>' {
+			t.readChar()
+			tok = token.Token{Type: token.THIN_ARROW, Literal: "->"}
+		} else {
+			tok = newToken(token.MINUS, t.ch)
+		}
 	case '/':
 		tok = newToken(token.SLASH, t.ch)
 	case '*':
@@ -51,6 +57,9 @@ func (t *Tokenizer) NextToken() token.Token {
 			} else {
 				tok = token.Token{Type: token.VAL_ASSIGN, Literal: "=*"}
 			}
+		case '>':
+			t.readChar()
+			tok = token.Token{Type: token.ARROW, Literal: "=>"}
 		default:
 			tok = newToken(token.ASSIGN, t.ch)
 		}
@@ -92,11 +101,27 @@ func (t *Tokenizer) NextToken() token.Token {
 	case '&':
 		tok = newToken(token.AND, t.ch)
 	case '|':
-		tok = newToken(token.OR, t.ch)
+		if t.peekChar() == '>' {
+			t.readChar()
+			tok = token.Token{Type: token.PIPE, Literal: "|>"}
+		} else {
+			tok = newToken(token.OR, t.ch)
+		}
 	case ',':
 		tok = newToken(token.COMMA, t.ch)
 	case '.':
 		tok = newToken(token.DOT, t.ch)
+	case '?':
+		switch t.peekChar() {
+		case '.':
+			t.readChar()
+			tok = token.Token{Type: token.OPTIONAL_DOT, Literal: "?."}
+		case '?':
+			t.readChar()
+			tok = token.Token{Type: token.COALESCE, Literal: "??"}
+		default:
+			tok = newToken(token.ILLEGAL, t.ch)
+		}
 	case ';':
 		tok = newToken(token.SEMICOLON, t.ch)
 	case ':':
@@ -113,9 +138,20 @@ func (t *Tokenizer) NextToken() token.Token {
 		tok = newToken(token.LBRACKET, t.ch)
 	case ']':
 		tok = newToken(token.RBRACKET, t.ch)
+	case '#':
+		if t.peekChar() == '{' {
+			t.readChar()
+			tok = token.Token{Type: token.SET_LBRACE, Literal: "#{"}
+		} else {
+			tok = newToken(token.ILLEGAL, t.ch)
+		}
 	case '"':
 		tok.Type = token.STRING
-		tok.Literal = t.readString()
+		literal, template := t.readString()
+		if template {
+			tok.Type = token.TEMPLATE
+		}
+		tok.Literal = literal
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
@@ -139,7 +175,7 @@ func (t *Tokenizer) NextToken() token.Token {
 
 // Peeks the next character in input without modifying indexes
 func (t *Tokenizer) peekChar() byte {
-	if t.readPosition > len(t.input) {
+	if t.readPosition >= len(t.input) {
 		return 0
 	} else {
 		return t.input[t.readPosition]
@@ -164,15 +200,90 @@ func (t *Tokenizer) readNumber() string {
 	return t.input[position:t.position]
 }
 
-// Fully read String
-func (t *Tokenizer) readString() string {
+// Fully read String, and whether it contains interpolated expressions
+func (t *Tokenizer) readString() (string, bool) {
 	t.readChar() // Skip opening quotes
 	position := t.position
+	template := false
 	for t.ch != 0 && t.ch != '"' {
+		if t.ch == '\\' && t.peekChar() != 0 { // Escaped character, may be a quote
+			t.readChar()
+		} else if t.ch == '$' && t.peekChar() == '{' {
+			template = true
+			t.readChar()
+			t.skipInterpolation()
+			if t.ch == 0 {
+				break
+			}
+		}
 		t.readChar()
+	}
+	return t.input[position:t.position], template
+}
 
+// Returns the text of a string literal with its escape sequences(\n, \t, \" and \\) replaced by the characters
+// they stand for, other backslashes are kept as written
+func Unescape(literal string) string {
+	var out strings.Builder
+	for i := 0; i < len(literal); i++ {
+		if literal[i] != '\\' || i+1 == len(literal) {
+			out.WriteByte(literal[i])
+			continue
+		}
+		switch literal[i+1] {
+		case 'n':
+			out.WriteByte('\n')
+		case 't':
+			out.WriteByte('\t')
+		case '"', '\\':
+			out.WriteByte(literal[i+1])
+		default:
+			out.WriteByte('\\')
+			out.WriteByte(literal[i+1])
+		}
+		i++
 	}
-	return t.input[position:t.position]
+	return out.String()
+}
+
+// Moves to the brace closing an interpolated expression, skipping nested braces and strings
+func (t *Tokenizer) skipInterpolation() {
+	depth := 1
+	for depth > 0 && t.ch != 0 {
+		t.readChar()
+		switch t.ch {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '"':
+			t.readString()
+		}
+	}
+}
+
+// Splits the literal of a template string into its text and the source of its interpolated expressions.
+// Text surrounds the expressions so there is always one more text than expressions,
+// ok is false when an interpolation is not closed
+func SplitTemplate(literal string) (texts []string, exprs []string, ok bool) {
+	t := New(literal)
+	start := 0
+	for t.ch != 0 {
+		if t.ch == '$' && t.peekChar() == '{' {
+			texts = append(texts, literal[start:t.position])
+			t.readChar()
+			exprStart := t.readPosition
+			t.skipInterpolation()
+			if t.ch == 0 {
+				return texts, exprs, false
+			}
+			exprs = append(exprs, literal[exprStart:t.position])
+			start = t.readPosition
+		}
+		t.readChar()
+	}
+	texts = append(texts, literal[start:])
+	return texts, exprs, true
 }
 
 // Reads next character of input
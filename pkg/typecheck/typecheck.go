@@ -0,0 +1,904 @@
+package typecheck
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Youssef-Mak/baby-interpreter/pkg/ast"
+	"github.com/Youssef-Mak/baby-interpreter/pkg/token"
+)
+
+/* TYPES */
+
+// Static type of an expression, written the same way as annotations
+type Type interface {
+	String() string
+}
+
+type basicType string
+
+func (bt basicType) String() string { return string(bt) }
+
+var (
+	INT    = basicType("int")
+	BOOL   = basicType("bool")
+	STRING = basicType("string")
+	NULL   = basicType("null")
+	ANY    = basicType("any") // Unknown type, compatible with every type
+)
+
+var basicTypes = map[string]Type{
+	"int":    INT,
+	"bool":   BOOL,
+	"string": STRING,
+	"null":   NULL,
+	"any":    ANY,
+}
+
+type arrayType struct{ element Type }
+
+func (at *arrayType) String() string { return "[" + at.element.String() + "]" }
+
+type hashType struct{ key, value Type }
+
+func (ht *hashType) String() string { return "{" + ht.key.String() + ": " + ht.value.String() + "}" }
+
+type setType struct{ element Type }
+
+func (st *setType) String() string { return "#{" + st.element.String() + "}" }
+
+type functionType struct {
+	parameters []Type // nil when the arguments are not checked(builtins)
+	ret        Type
+}
+
+func (ft *functionType) String() string {
+	if ft.parameters == nil {
+		return "fun(...) -> " + ft.ret.String()
+	}
+	params := []string{}
+	for _, p := range ft.parameters {
+		params = append(params, p.String())
+	}
+	return "fun(" + strings.Join(params, ", ") + ") -> " + ft.ret.String()
+}
+
+// Type of the values constructed by a struct definition
+type structType struct {
+	name   string
+	fields []*ast.Identifier
+	types  []Type
+}
+
+func (st *structType) String() string { return st.name }
+
+// Returns type of field
+func (st *structType) field(name string) (Type, bool) {
+	for i, f := range st.fields {
+		if f.Value == name {
+			return st.types[i], true
+		}
+	}
+	return nil, false
+}
+
+// Type of the values constructed by the variants of a tagged union
+type unionType struct {
+	name     string
+	variants []*structType
+}
+
+func (ut *unionType) String() string { return ut.name }
+
+// Returns type of a field declared by any of the variants, any when the variants declare it with different types
+func (ut *unionType) field(name string) (Type, bool) {
+	var shared Type
+	for _, variant := range ut.variants {
+		if t, ok := variant.field(name); ok {
+			if shared == nil {
+				shared = t
+			} else {
+				shared = unify(shared, t)
+			}
+		}
+	}
+	return shared, shared != nil
+}
+
+// Types whose values have named fields
+type fieldsType interface {
+	Type
+	field(name string) (Type, bool)
+}
+
+// Return types of the builtins whose result is known, their arguments are checked at runtime
+var builtinTypes = map[string]Type{
+	"len":         &functionType{ret: INT},
+	"indexOf":     &functionType{ret: INT},
+	"int":         &functionType{ret: INT},
+	"str":         &functionType{ret: STRING},
+	"type":        &functionType{ret: STRING},
+	"tag":         &functionType{ret: STRING},
+	"format":      &functionType{ret: STRING},
+	"join":        &functionType{ret: STRING},
+	"trim":        &functionType{ret: STRING},
+	"upper":       &functionType{ret: STRING},
+	"lower":       &functionType{ret: STRING},
+	"replace":     &functionType{ret: STRING},
+	"substring":   &functionType{ret: STRING},
+	"repeat":      &functionType{ret: STRING},
+	"padLeft":     &functionType{ret: STRING},
+	"padRight":    &functionType{ret: STRING},
+	"split":       &functionType{ret: &arrayType{element: STRING}},
+	"range":       &functionType{ret: &arrayType{element: INT}},
+	"bool":        &functionType{ret: BOOL},
+	"contains":    &functionType{ret: BOOL},
+	"startsWith":  &functionType{ret: BOOL},
+	"endsWith":    &functionType{ret: BOOL},
+	"any":         &functionType{ret: BOOL},
+	"all":         &functionType{ret: BOOL},
+	"isFrozen":    &functionType{ret: BOOL},
+	"isEmpty":     &functionType{ret: BOOL},
+	"has":         &functionType{ret: BOOL},
+	"keys":        &functionType{ret: &arrayType{element: ANY}},
+	"values":      &functionType{ret: &arrayType{element: ANY}},
+	"entries":     &functionType{ret: &arrayType{element: &arrayType{element: ANY}}},
+	"fromEntries": &functionType{ret: &hashType{key: ANY, value: ANY}},
+	"delete":      &functionType{ret: &hashType{key: ANY, value: ANY}},
+	"merge":       &functionType{ret: &hashType{key: ANY, value: ANY}},
+	"print":       &functionType{ret: NULL},
+	"printf":      &functionType{ret: NULL},
+}
+
+// Returns whether a value of type from can be used where type to is expected
+func assignable(from Type, to Type) bool {
+	if from == ANY || to == ANY {
+		return true
+	}
+	switch to := to.(type) {
+	case *arrayType:
+		from, ok := from.(*arrayType)
+		return ok && assignable(from.element, to.element)
+	case *hashType:
+		from, ok := from.(*hashType)
+		return ok && assignable(from.key, to.key) && assignable(from.value, to.value)
+	case *setType:
+		from, ok := from.(*setType)
+		return ok && assignable(from.element, to.element)
+	case *functionType:
+		from, ok := from.(*functionType)
+		if !ok {
+			return false
+		}
+		if from.parameters != nil && to.parameters != nil {
+			if len(from.parameters) != len(to.parameters) {
+				return false
+			}
+			for i, param := range to.parameters {
+				if !assignable(param, from.parameters[i]) {
+					return false
+				}
+			}
+		}
+		return assignable(from.ret, to.ret)
+	default:
+		return from == to
+	}
+}
+
+// Returns the type both types fit, any when they differ
+func unify(a Type, b Type) Type {
+	if a.String() == b.String() {
+		return a
+	}
+	return ANY
+}
+
+// Returns the kind of a type(array, hash...) ignoring the types it contains
+func kindOf(t Type) string {
+	switch t.(type) {
+	case *arrayType:
+		return "array"
+	case *hashType:
+		return "hash"
+	case *setType:
+		return "set"
+	case *functionType:
+		return "function"
+	default:
+		return t.String()
+	}
+}
+
+/* SCOPES */
+
+type binding struct {
+	typ       Type
+	annotated bool // Annotated bindings keep their type, others take the type of what is assigned
+}
+
+type scope struct {
+	bindings map[string]*binding
+	types    map[string]Type // Struct and tagged union types declared in the scope, usable in annotations
+	outer    *scope
+}
+
+func newScope(outer *scope) *scope {
+	return &scope{bindings: map[string]*binding{}, types: map[string]Type{}, outer: outer}
+}
+
+// Returns a scope with copies of the bindings and types, so updating them leaves s unchanged
+func (s *scope) copy() *scope {
+	copied := newScope(s.outer)
+	for name, b := range s.bindings {
+		copiedBinding := *b
+		copied.bindings[name] = &copiedBinding
+	}
+	for name, t := range s.types {
+		copied.types[name] = t
+	}
+	return copied
+}
+
+func (s *scope) lookupType(name string) (Type, bool) {
+	for current := s; current != nil; current = current.outer {
+		if t, ok := current.types[name]; ok {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+func (s *scope) lookup(name string) (*binding, bool) {
+	for current := s; current != nil; current = current.outer {
+		if b, ok := current.bindings[name]; ok {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+/* CHECKER */
+
+type checker struct {
+	diagnostics   []string
+	scope         *scope
+	function      *ast.FunctionLiteral // Function whose body is being checked, nil at the top level
+	reassigned    map[string]bool      // Names re-assigned anywhere in the program
+	fieldAssigned map[string]bool      // Names whose fields are assigned anywhere in the program
+}
+
+// Session checks programs one after the other, like the lines of a REPL, each seeing the declarations
+// of the programs before it
+type Session struct {
+	scope *scope
+}
+
+func NewSession() *Session {
+	return &Session{scope: newScope(nil)}
+}
+
+// Returns the diagnostics of the type errors found in the program, without running it.
+// A program with diagnostics is not run, so its declarations are discarded
+func (s *Session) Check(program *ast.Program) []string {
+	c := &checker{
+		diagnostics:   []string{},
+		scope:         s.scope.copy(),
+		reassigned:    map[string]bool{},
+		fieldAssigned: map[string]bool{},
+	}
+	for _, statement := range program.Statements {
+		c.collectAssignments(statement)
+	}
+
+	c.checkStatements(program.Statements)
+	if len(c.diagnostics) == 0 {
+		s.scope = c.scope
+	}
+	return c.diagnostics
+}
+
+// Returns the diagnostics of the type errors found in the program, without running it.
+// Unannotated values are inferred where possible and are otherwise of type any
+func Check(program *ast.Program) []string {
+	return NewSession().Check(program)
+}
+
+// Adds a diagnostic, once even when annotations are resolved again
+func (c *checker) report(format string, a ...interface{}) {
+	msg := fmt.Sprintf(format, a...)
+	for _, reported := range c.diagnostics {
+		if reported == msg {
+			return
+		}
+	}
+	c.diagnostics = append(c.diagnostics, msg)
+}
+
+// Returns the type written by an annotation, any when absent
+func (c *checker) resolve(annotation ast.TypeAnnotation) Type {
+	switch annotation := annotation.(type) {
+	case nil:
+		return ANY
+	case *ast.NamedType:
+		if t, ok := basicTypes[annotation.Name]; ok {
+			return t
+		}
+		if t, ok := c.scope.lookupType(annotation.Name); ok {
+			return t
+		}
+		c.report("unknown type: %s", annotation.Name)
+		return ANY
+	case *ast.ArrayType:
+		return &arrayType{element: c.resolve(annotation.Element)}
+	case *ast.HashType:
+		return &hashType{key: c.resolve(annotation.Key), value: c.resolve(annotation.Value)}
+	case *ast.SetType:
+		return &setType{element: c.resolve(annotation.Element)}
+	case *ast.FunctionType:
+		params := []Type{}
+		for _, p := range annotation.Parameters {
+			params = append(params, c.resolve(p))
+		}
+		return &functionType{parameters: params, ret: c.resolve(annotation.Return)}
+	default:
+		return ANY
+	}
+}
+
+// Returns the type of a function from the annotations of its signature
+func (c *checker) signature(fun *ast.FunctionLiteral) *functionType {
+	params := []Type{}
+	for _, p := range fun.Parameters {
+		params = append(params, c.resolve(p.Annotation))
+	}
+	return &functionType{parameters: params, ret: c.resolve(fun.ReturnType)}
+}
+
+func (c *checker) checkStatements(statements []ast.Statement) {
+	// Structs, tagged unions and named functions are hoisted, so they can be used before their declaration
+	defined := []*structType{}
+	constructed := map[*structType]Type{} // Type of the values built by each constructor
+	for _, statement := range statements {
+		switch def := statement.(type) {
+		case *ast.StructDefinition:
+			if def == nil {
+				continue
+			}
+			st := &structType{name: def.Name.Value, fields: def.Fields}
+			c.scope.types[st.name] = st
+			defined = append(defined, st)
+			constructed[st] = st
+		case *ast.TypeDefinition:
+			if def == nil {
+				continue
+			}
+			ut := &unionType{name: def.Name.Value}
+			c.scope.types[ut.name] = ut
+			for _, variant := range def.Variants {
+				st := &structType{name: variant.Name.Value, fields: variant.Fields}
+				ut.variants = append(ut.variants, st)
+				defined = append(defined, st)
+				constructed[st] = ut
+			}
+		}
+	}
+	for _, st := range defined { // Fields are resolved once every type is known, so types can refer to each other
+		st.types = []Type{}
+		for _, f := range st.fields {
+			st.types = append(st.types, c.resolve(f.Annotation))
+		}
+		if _, isVariant := constructed[st].(*unionType); isVariant && len(st.fields) == 0 {
+			c.scope.bindings[st.name] = &binding{typ: constructed[st], annotated: true}
+			continue
+		}
+		c.scope.bindings[st.name] = &binding{typ: &functionType{parameters: st.types, ret: constructed[st]}, annotated: true}
+	}
+	for _, statement := range statements {
+		if decl, ok := statement.(*ast.FunctionDeclaration); ok && decl != nil {
+			c.scope.bindings[decl.Name.Value] = &binding{typ: c.signature(decl.Function), annotated: true}
+		}
+	}
+	for _, statement := range statements {
+		c.checkStatement(statement)
+	}
+}
+
+func (c *checker) checkStatement(statement ast.Statement) {
+	switch statement := statement.(type) {
+	case *ast.AssignmentStatement:
+		if statement == nil {
+			return
+		}
+		c.checkAssignment(statement)
+	case *ast.ReturnStatement:
+		if statement == nil {
+			return
+		}
+		c.checkReturn(c.infer(statement.ReturnValue))
+	case *ast.ExpressionStatement:
+		if statement == nil {
+			return
+		}
+		c.infer(statement.Expression)
+	case *ast.FunctionDeclaration:
+		if statement == nil {
+			return
+		}
+		c.checkFunction(statement.Function)
+	case *ast.ThrowStatement:
+		if statement == nil {
+			return
+		}
+		c.infer(statement.Value)
+	case *ast.FieldAssignmentStatement:
+		if statement == nil {
+			return
+		}
+		fieldType := c.infer(statement.Target)
+		valueType := c.infer(statement.Value)
+		if !assignable(valueType, fieldType) {
+			target := statement.Target.Left.String() + "." + statement.Target.Attribute.String()
+			c.report("cannot assign %s to %s: %s", valueType, target, fieldType)
+		}
+	case *ast.BlockStatement:
+		c.checkBlock(statement)
+	}
+}
+
+func (c *checker) checkAssignment(statement *ast.AssignmentStatement) {
+	valueType := c.infer(statement.Value)
+	name := statement.Name.Value
+
+	if statement.Token.Type != token.IDENTIF { // let or const declaration
+		if statement.Name.Annotation == nil {
+			c.scope.bindings[name] = &binding{typ: c.declaredType(name, valueType)}
+			return
+		}
+		declared := c.resolve(statement.Name.Annotation)
+		if !assignable(valueType, declared) {
+			c.report("cannot assign %s to %s: %s", valueType, name, declared)
+		}
+		c.scope.bindings[name] = &binding{typ: declared, annotated: true}
+		return
+	}
+
+	b, ok := c.scope.lookup(name)
+	if !ok {
+		return
+	}
+	if b.annotated {
+		if !assignable(valueType, b.typ) {
+			c.report("cannot assign %s to %s: %s", valueType, name, b.typ)
+		}
+	} else {
+		b.typ = unify(b.typ, valueType)
+	}
+}
+
+// Returns the type of an unannotated declaration. The checker does not follow the order in which statements run,
+// so names re-assigned anywhere(closures can run after a later re-assignment) are any, and hashes whose fields
+// are assigned can hold values of any type
+func (c *checker) declaredType(name string, valueType Type) Type {
+	if c.reassigned[name] {
+		return ANY
+	}
+	if hash, ok := valueType.(*hashType); ok && c.fieldAssigned[name] {
+		return &hashType{key: unify(hash.key, STRING), value: ANY}
+	}
+	return valueType
+}
+
+// Records the names re-assigned and the names whose fields are assigned within node
+func (c *checker) collectAssignments(node ast.Node) {
+	switch node := node.(type) {
+	case *ast.AssignmentStatement:
+		if node == nil {
+			return
+		}
+		if node.Token.Type == token.IDENTIF {
+			c.reassigned[node.Name.Value] = true
+		}
+		c.collectAssignments(node.Value)
+	case *ast.FieldAssignmentStatement:
+		if node == nil {
+			return
+		}
+		if name, ok := node.Target.Left.(*ast.Identifier); ok {
+			c.fieldAssigned[name.Value] = true
+		}
+		c.collectAssignments(node.Target)
+		c.collectAssignments(node.Value)
+	case *ast.ExpressionStatement:
+		if node != nil {
+			c.collectAssignments(node.Expression)
+		}
+	case *ast.ReturnStatement:
+		if node != nil {
+			c.collectAssignments(node.ReturnValue)
+		}
+	case *ast.ThrowStatement:
+		if node != nil {
+			c.collectAssignments(node.Value)
+		}
+	case *ast.FunctionDeclaration:
+		if node != nil {
+			c.collectAssignments(node.Function)
+		}
+	case *ast.BlockStatement:
+		if node == nil {
+			return
+		}
+		for _, statement := range node.Statements {
+			c.collectAssignments(statement)
+		}
+	case *ast.FunctionLiteral:
+		c.collectAssignments(node.Body)
+	case *ast.IfExpression:
+		c.collectAssignments(node.Condition)
+		c.collectAssignments(node.Consequence)
+		c.collectAssignments(node.Alternative)
+	case *ast.WhileExpression:
+		c.collectAssignments(node.Condition)
+		c.collectAssignments(node.Body)
+	case *ast.TryExpression:
+		c.collectAssignments(node.Block)
+		c.collectAssignments(node.Catch)
+		c.collectAssignments(node.Finally)
+	case *ast.CallExpression:
+		c.collectAssignments(node.Function)
+		for _, arg := range node.Arguments {
+			c.collectAssignments(arg)
+		}
+	case *ast.PipeExpression:
+		c.collectAssignments(node.Left)
+		c.collectAssignments(node.Right)
+	case *ast.InfixExpression:
+		c.collectAssignments(node.Left)
+		c.collectAssignments(node.Right)
+	case *ast.PrefixExpression:
+		c.collectAssignments(node.Right)
+	case *ast.IndexExpression:
+		c.collectAssignments(node.Left)
+		c.collectAssignments(node.Index)
+	case *ast.SliceExpression:
+		c.collectAssignments(node.Left)
+		c.collectAssignments(node.Start)
+		c.collectAssignments(node.End)
+	case *ast.DotExpression:
+		c.collectAssignments(node.Left)
+		c.collectAssignments(node.Attribute)
+	case *ast.ArrayLiteral:
+		for _, e := range node.Elements {
+			c.collectAssignments(e)
+		}
+	case *ast.SetLiteral:
+		for _, e := range node.Elements {
+			c.collectAssignments(e)
+		}
+	case *ast.HashLiteral:
+		for _, key := range node.Keys {
+			c.collectAssignments(key)
+			c.collectAssignments(node.Pairs[key])
+		}
+	case *ast.TemplateLiteral:
+		for _, part := range node.Parts {
+			c.collectAssignments(part)
+		}
+	}
+}
+
+func (c *checker) checkReturn(valueType Type) {
+	if c.function == nil || c.function.ReturnType == nil {
+		return
+	}
+	expected := c.resolve(c.function.ReturnType)
+	if !assignable(valueType, expected) {
+		c.report("cannot return %s from %s: expected %s", valueType, functionName(c.function), expected)
+	}
+}
+
+func (c *checker) checkBlock(block *ast.BlockStatement) {
+	if block == nil {
+		return
+	}
+	outer := c.scope
+	c.scope = newScope(outer)
+	c.checkStatements(block.Statements)
+	c.scope = outer
+}
+
+// Checks the body of a function and returns its type, the return type is inferred from single expression bodies
+func (c *checker) checkFunction(fun *ast.FunctionLiteral) Type {
+	funType := c.signature(fun)
+
+	outerScope, outerFunction := c.scope, c.function
+	c.scope, c.function = newScope(outerScope), fun
+	for i, param := range fun.Parameters {
+		c.scope.bindings[param.Value] = &binding{typ: funType.parameters[i], annotated: param.Annotation != nil}
+	}
+
+	statements := fun.Body.Statements
+	if len(statements) > 0 {
+		c.checkStatements(statements[:len(statements)-1])
+		// The last expression is the implicit return value
+		last := statements[len(statements)-1]
+		if exprStatement, ok := last.(*ast.ExpressionStatement); ok && exprStatement != nil {
+			c.checkReturn(c.infer(exprStatement.Expression))
+		} else if ret, ok := last.(*ast.ReturnStatement); ok && ret != nil && len(statements) == 1 {
+			retType := c.infer(ret.ReturnValue)
+			c.checkReturn(retType)
+			if fun.ReturnType == nil {
+				funType.ret = retType
+			}
+		} else {
+			c.checkStatement(last)
+		}
+	}
+
+	c.scope, c.function = outerScope, outerFunction
+	return funType
+}
+
+func functionName(fun *ast.FunctionLiteral) string {
+	if fun.Name == "" {
+		return "<anonymous>"
+	}
+	return fun.Name
+}
+
+/* INFERENCE */
+
+// Returns the type of an expression, reporting the type errors within it
+func (c *checker) infer(expr ast.Expression) Type {
+	switch expr := expr.(type) {
+	case *ast.IntegerLiteral:
+		return INT
+	case *ast.StringLiteral:
+		return STRING
+	case *ast.Boolean:
+		return BOOL
+	case *ast.TemplateLiteral:
+		for _, part := range expr.Parts {
+			c.infer(part)
+		}
+		return STRING
+	case *ast.Identifier:
+		if b, ok := c.scope.lookup(expr.Value); ok {
+			return b.typ
+		}
+		if t, ok := builtinTypes[expr.Value]; ok {
+			return t
+		}
+		return ANY
+	case *ast.ArrayLiteral:
+		return &arrayType{element: c.inferAll(expr.Elements)}
+	case *ast.SetLiteral:
+		return &setType{element: c.inferAll(expr.Elements)}
+	case *ast.HashLiteral:
+		values := []ast.Expression{}
+		for _, key := range expr.Keys {
+			values = append(values, expr.Pairs[key])
+		}
+		return &hashType{key: c.inferAll(expr.Keys), value: c.inferAll(values)}
+	case *ast.PrefixExpression:
+		return c.inferPrefix(expr.Operator, c.infer(expr.Right))
+	case *ast.InfixExpression:
+		return c.inferInfix(expr.Operator, c.infer(expr.Left), c.infer(expr.Right))
+	case *ast.CallExpression:
+		funType := c.infer(expr.Function)
+		return c.inferCall(expr.Function.String(), funType, c.inferEach(expr.Arguments))
+	case *ast.PipeExpression:
+		args := []Type{c.infer(expr.Left)}
+		if call, ok := expr.Right.(*ast.CallExpression); ok {
+			funType := c.infer(call.Function)
+			return c.inferCall(call.Function.String(), funType, append(args, c.inferEach(call.Arguments)...))
+		}
+		return c.inferCall(expr.Right.String(), c.infer(expr.Right), args)
+	case *ast.IndexExpression:
+		return c.inferIndex(c.infer(expr.Left), c.infer(expr.Index))
+	case *ast.SliceExpression:
+		left := c.infer(expr.Left)
+		for _, bound := range []ast.Expression{expr.Start, expr.End} {
+			if bound != nil {
+				if boundType := c.infer(bound); !assignable(boundType, INT) {
+					c.report("cannot slice with %s", boundType)
+				}
+			}
+		}
+		switch left.(type) {
+		case *arrayType:
+			return left
+		default:
+			if left == STRING || left == ANY {
+				return left
+			}
+			c.report("cannot slice %s", left)
+			return ANY
+		}
+	case *ast.DotExpression:
+		left := c.infer(expr.Left)
+		if fielded, ok := left.(fieldsType); ok {
+			field, isIdent := expr.Attribute.(*ast.Identifier)
+			if !isIdent {
+				return ANY
+			}
+			fieldType, found := fielded.field(field.Value)
+			if !found {
+				c.report("%s has no field %s", fielded, field.Value)
+				return ANY
+			}
+			return fieldType
+		}
+		keyType := Type(STRING) // ".name" is the key "name"
+		if _, isName := expr.Attribute.(*ast.Identifier); !isName {
+			keyType = c.infer(expr.Attribute)
+		}
+		if hash, ok := left.(*hashType); ok {
+			if !assignable(keyType, hash.key) {
+				c.report("cannot index %s with %s", hash, keyType)
+			}
+			return hash.value
+		}
+		return ANY
+	case *ast.IfExpression:
+		c.infer(expr.Condition)
+		c.checkBlock(expr.Consequence)
+		c.checkBlock(expr.Alternative)
+		return ANY
+	case *ast.WhileExpression:
+		c.infer(expr.Condition)
+		c.checkBlock(expr.Body)
+		return ANY
+	case *ast.TryExpression:
+		c.checkBlock(expr.Block)
+		if expr.Catch != nil {
+			outer := c.scope
+			c.scope = newScope(outer)
+			c.scope.bindings[expr.Param.Value] = &binding{typ: &hashType{key: STRING, value: ANY}}
+			c.checkStatements(expr.Catch.Statements)
+			c.scope = outer
+		}
+		c.checkBlock(expr.Finally)
+		return ANY
+	case *ast.FunctionLiteral:
+		return c.checkFunction(expr)
+	default:
+		return ANY
+	}
+}
+
+func (c *checker) inferEach(exprs []ast.Expression) []Type {
+	types := []Type{}
+	for _, e := range exprs {
+		types = append(types, c.infer(e))
+	}
+	return types
+}
+
+// Returns the type shared by all expressions, any if they differ or there are none
+func (c *checker) inferAll(exprs []ast.Expression) Type {
+	types := c.inferEach(exprs)
+	if len(types) == 0 {
+		return ANY
+	}
+	shared := types[0]
+	for _, t := range types[1:] {
+		shared = unify(shared, t)
+	}
+	return shared
+}
+
+func (c *checker) inferPrefix(operator string, right Type) Type {
+	switch operator {
+	case "!":
+		return BOOL
+	case "-":
+		if !assignable(right, INT) {
+			c.report("unknown operator: -%s", right)
+		}
+		return INT
+	default:
+		return ANY
+	}
+}
+
+func (c *checker) inferInfix(operator string, left Type, right Type) Type {
+	switch operator {
+	case "=&=", "=*=", "!&=", "!*=":
+		return BOOL
+	case "??":
+		if left == NULL {
+			return right
+		}
+		return unify(left, right)
+	}
+
+	if left == ANY || right == ANY {
+		switch operator {
+		case "<", ">", "<=", ">=":
+			return BOOL
+		case "-", "*", "/":
+			if left == INT || right == INT {
+				return INT
+			}
+		}
+		return ANY
+	}
+
+	switch {
+	case left == INT && right == INT:
+		switch operator {
+		case "+", "-", "*", "/":
+			return INT
+		case "<", ">", "<=", ">=":
+			return BOOL
+		}
+	case left == STRING && right == STRING:
+		if operator == "+" {
+			return STRING
+		}
+	case kindOf(left) == "set" && kindOf(right) == "set":
+		switch operator {
+		case "|", "&", "-":
+			return unify(left, right)
+		}
+	case operator == "&" || operator == "|":
+		return BOOL
+	case kindOf(left) != kindOf(right):
+		c.report("type mismatch: %s %s %s", left, operator, right)
+		return ANY
+	}
+	c.report("unknown operator: %s %s %s", left, operator, right)
+	return ANY
+}
+
+func (c *checker) inferCall(name string, callee Type, args []Type) Type {
+	switch callee := callee.(type) {
+	case *functionType:
+		if callee.parameters == nil {
+			return callee.ret
+		}
+		if len(args) != len(callee.parameters) {
+			c.report("%s expects %d arguments, got %d", name, len(callee.parameters), len(args))
+			return callee.ret
+		}
+		for i, arg := range args {
+			if !assignable(arg, callee.parameters[i]) {
+				c.report("argument %d of %s: cannot use %s as %s", i+1, name, arg, callee.parameters[i])
+			}
+		}
+		return callee.ret
+	default:
+		if callee != ANY {
+			c.report("%s is not callable: %s", name, callee)
+		}
+		return ANY
+	}
+}
+
+func (c *checker) inferIndex(left Type, index Type) Type {
+	switch left := left.(type) {
+	case *hashType:
+		if !assignable(index, left.key) {
+			c.report("cannot index %s with %s", left, index)
+		}
+		return left.value
+	case *arrayType:
+		if !assignable(index, INT) {
+			c.report("cannot index %s with %s", left, index)
+		}
+		return left.element
+	default:
+		if left == STRING {
+			if !assignable(index, INT) {
+				c.report("cannot index %s with %s", left, index)
+			}
+			return STRING
+		}
+		if left != ANY {
+			c.report("cannot index %s", left)
+		}
+		return ANY
+	}
+}
@@ -1,6 +1,8 @@
 package tests
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/Youssef-Mak/baby-interpreter/pkg/evaluator"
@@ -45,6 +47,19 @@ func testIntegerObject(t *testing.T, obj object.Object, expected int64) bool {
 	return true
 }
 
+func testErrorObject(t *testing.T, obj object.Object, expected string) bool {
+	errObj, ok := obj.(*object.Error)
+	if !ok {
+		t.Errorf("object is not Error. got=%T (%+v)", obj, obj)
+		return false
+	}
+	if errObj.Message != expected {
+		t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+		return false
+	}
+	return true
+}
+
 func testNullObject(t *testing.T, obj object.Object) bool {
 	if obj != evaluator.NULL {
 		t.Errorf("object is not NULL. got=%T (%+v)", obj, obj)
@@ -122,6 +137,289 @@ func TestStringConcatenation(t *testing.T) {
 	}
 }
 
+func TestStringBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`len("héllo")`, 5},
+		{`head("éa")`, "é"},
+		{`tail("aé")`, "é"},
+		{`rest("éab")`, "ab"},
+		{`head("")`, nil},
+		{`"héllo"[1]`, "é"},
+		{`"héllo"[5]`, nil},
+		{`"abc"[-1]`, "c"},
+		{`"abc"[-4]`, nil},
+		{`split("a,b,c", ",")`, []string{"a", "b", "c"}},
+		{`split("hé", "")`, []string{"h", "é"}},
+		{`join(["a", "b", "c"], ", ")`, "a, b, c"},
+		{`join([], ", ")`, ""},
+		{`trim("  hi	 ")`, "hi"},
+		{`upper("héllo")`, "HÉLLO"},
+		{`lower("HÉLLO")`, "héllo"},
+		{`replace("a-b-c", "-", "+")`, "a+b+c"},
+		{`contains("hello", "ell")`, true},
+		{`contains("hello", "z")`, false},
+		{`startsWith("hello", "he")`, true},
+		{`endsWith("hello", "he")`, false},
+		{`indexOf("héllo", "l")`, 2},
+		{`indexOf("héllo", "z")`, -1},
+		{`substring("héllo", 1, 3)`, "él"},
+		{`substring("héllo", 2)`, "llo"},
+		{`repeat("ab", 3)`, "ababab"},
+		{`repeat("ab", 0)`, ""},
+		{`padLeft("7", 3, "0")`, "007"},
+		{`padLeft("é", 3)`, "  é"},
+		{`padRight("ab", 5, "xy")`, "abxyx"},
+		{`padRight("abc", 2)`, "abc"},
+		{`"  a,b,c " |> trim |> split(",") |> map(upper) |> join("-")`, "A-B-C"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case nil:
+			testNullObject(t, evaluated)
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok {
+				t.Errorf("input=%q object is not String. got=%T (%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if str.Value != expected {
+				t.Errorf("input=%q String has wrong value. expected=%q, got=%q", tt.input, expected, str.Value)
+			}
+		case []string:
+			array, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("input=%q object is not Array. got=%T (%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if len(array.Elements) != len(expected) {
+				t.Errorf("input=%q wrong num of elements. expected=%d, got=%d", tt.input, len(expected), len(array.Elements))
+				continue
+			}
+			for i, e := range expected {
+				if str, ok := array.Elements[i].(*object.String); !ok || str.Value != e {
+					t.Errorf("input=%q element %d wrong. expected=%q, got=%s", tt.input, i, e, array.Elements[i].Inspect())
+				}
+			}
+		}
+	}
+}
+
+func TestStringBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`split("a", 1)`, "argument to `split` not supported, expected String, got INTEGER"},
+		{`join(["a", 1], "")`, "argument to `join` not supported, expected String, got INTEGER"},
+		{`join("a", "")`, "argument to `join` not supported, got STRING"},
+		{`upper(1)`, "argument to `upper` not supported, expected String, got INTEGER"},
+		{`substring("abc", 2, 1)`, "substring bounds out of range [2:1] with length 3"},
+		{`substring("abc", 0, 4)`, "substring bounds out of range [0:4] with length 3"},
+		{`substring("abc", "a")`, "argument to `substring` not supported, expected Integer, got STRING"},
+		{`repeat("a", -1)`, "argument to `repeat` not supported, count cannot be negative, got -1"},
+		{`padLeft("a", 3, "")`, "argument to `padLeft` not supported, pad cannot be empty"},
+		{`padRight("a")`, "Call Arguments and function defined parameters size mismatch.\n Expected 2 to 3 arguments but got 1 parameter(s)"},
+		{`"abc"["a"]`, "expecting Integer Type but got STRING"},
+		{`1[0]`, "expecting Array, String or Hash Type but got INTEGER"},
+	}
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestTemplateLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let n = 3; "count: ${n}"`, "count: 3"},
+		{`"${1 + 2}${true}"`, "3true"},
+		{`let name = "baby"; "hi ${name}!"`, "hi baby!"},
+		{`"${[1, "a"]} and ${#{2}}"`, "[1, a] and #{2}"},
+		{`let n = 2; "${"nested ${n * 2}"}"`, "nested 4"},
+		{`let f = fun(x) { x + 1 }; "${f(1) |> f}"`, "3"},
+		{`"cost: $5 {a}"`, "cost: $5 {a}"},
+		{`"count: " + str(3)`, "count: 3"},
+		{`str("a")`, "a"},
+		{`str([1, 2])`, "[1, 2]"},
+		{`str(true)`, "true"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Errorf("input=%q object is not String. got=%T (%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if str.Value != tt.expected {
+			t.Errorf("input=%q String has wrong value. expected=%q, got=%q", tt.input, tt.expected, str.Value)
+		}
+	}
+
+	testErrorObject(t, testEval(`"a ${missing} b"`), "Identifier not Found: missing")
+}
+
+func TestTypeBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`type(1)`, "Integer"},
+		{`type(true)`, "Boolean"},
+		{`type("a")`, "String"},
+		{`type(if (false) { 1 })`, "Null"},
+		{`type([1])`, "Array"},
+		{`type({"a": 1})`, "Hash"},
+		{`type(#{1})`, "Set"},
+		{`type(fun(x) { x })`, "Function"},
+		{`type(len)`, "Function"},
+		{`isInt(1)`, true},
+		{`isInt("1")`, false},
+		{`isBool(false)`, true},
+		{`isString("a")`, true},
+		{`isNull(if (false) { 1 })`, true},
+		{`isArray([])`, true},
+		{`isHash({})`, true},
+		{`isSet(#{})`, true},
+		{`isFunction((x) => x)`, true},
+		{`isFunction(len)`, true},
+		{`isFunction([])`, false},
+		{`int("42")`, 42},
+		{`int(" -7 ")`, -7},
+		{`int(5)`, 5},
+		{`int(true)`, 1},
+		{`int(false)`, 0},
+		{`bool("true")`, true},
+		{`bool("false")`, false},
+		{`bool(0)`, false},
+		{`bool(3)`, true},
+		{`bool(true)`, true},
+		{`bool(if (false) { 1 })`, false},
+		{`str(int("42") + 1)`, "43"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok {
+				t.Errorf("input=%q object is not String. got=%T (%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if str.Value != expected {
+				t.Errorf("input=%q String has wrong value. expected=%q, got=%q", tt.input, expected, str.Value)
+			}
+		}
+	}
+}
+
+func TestTypeBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`int("4a")`, "cannot convert \"4a\" to Integer"},
+		{`int("")`, "cannot convert \"\" to Integer"},
+		{`int([1])`, "cannot convert Array to Integer"},
+		{`bool("yes")`, "cannot convert \"yes\" to Boolean"},
+		{`bool({})`, "cannot convert Hash to Boolean"},
+		{`type()`, "Call Arguments and function defined parameters size mismatch.\n Expected 1 arguments but got 0 parameter(s)"},
+		{`isInt(1, 2)`, "Call Arguments and function defined parameters size mismatch.\n Expected 1 arguments but got 2 parameter(s)"},
+	}
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFormatBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`format("plain")`, "plain"},
+		{`format("%d items", 3)`, "3 items"},
+		{`format("%5d|%-5d|%05d", 42, 42, 42)`, "   42|42   |00042"},
+		{`format("%+d", 5)`, "+5"},
+		{`format("%f %.2f %8.1f", 1, 2, -3)`, "1.000000 2.00     -3.0"},
+		{`format("%s and %v", "a", "b")`, "a and b"},
+		{`format("%-6s|%6s|", "ab", "héllo")`, "ab    | héllo|"},
+		{`format("%.3s", "héllo")`, "hél"},
+		{`format("%v %v %s", [1, "a"], true, #{1})`, "[1, a] true #{1}"},
+		{`format("100%%")`, "100%"},
+		{`format("%s\t%d\n", "a", 1)`, "a\t1\n"},
+		{`format("%s", "say \"${1 + 1}\"\n")`, "say \"2\"\n"},
+		{`format("%s: %d", "n", 1) |> upper`, "N: 1"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Errorf("input=%q object is not String. got=%T (%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if str.Value != tt.expected {
+			t.Errorf("input=%q String has wrong value. expected=%q, got=%q", tt.input, tt.expected, str.Value)
+		}
+	}
+}
+
+func TestPrintfTable(t *testing.T) {
+	input := `let rows = [["name", "score"], ["baby", 42], ["go", 7]];
+	printf("%-6s|%6s\n", rows[0][0], rows[0][1]);
+	printf("%-6s|%6.1f\n", rows[1][0], rows[1][1]);
+	printf("%-6s|%6d\n\tdone", rows[2][0], rows[2][1]);`
+	read, write, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("could not capture output: %s", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = write
+	evaluated := testEval(input)
+	os.Stdout = stdout
+	write.Close()
+	out, _ := ioutil.ReadAll(read)
+
+	testNullObject(t, evaluated)
+	expected := "name  | score\nbaby  |  42.0\ngo    |     7\n\tdone"
+	if string(out) != expected {
+		t.Errorf("printf printed wrong table. expected=%q, got=%q", expected, string(out))
+	}
+}
+
+func TestFormatBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`format()`, "Call Arguments and function defined parameters size mismatch.\n Expected at least 1 arguments but got 0 parameter(s)"},
+		{`format(1)`, "argument to `format` not supported, expected String, got INTEGER"},
+		{`format("%d", "a")`, "`format` verb %d expects INTEGER, got STRING"},
+		{`format("%.2f", "a")`, "`format` verb %.2f expects INTEGER, got STRING"},
+		{`format("%d %d", 1)`, "`format` is missing an argument for verb %d"},
+		{`format("%d", 1, 2)`, "`format` got 2 arguments but format uses 1"},
+		{`format("%z", 1)`, "`format` has unknown verb %z"},
+		{`format("50%")`, "`format` string ends with an incomplete verb \"%\""},
+		{`format("%1.2.3f", 1)`, "`format` verb %1.2. has more than one precision"},
+		{`format("%..f", 1)`, "`format` verb %.. has more than one precision"},
+		{`printf("%d")`, "`printf` is missing an argument for verb %d"},
+	}
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
 func TestBuiltinFunctions(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -153,6 +451,73 @@ func TestBuiltinFunctions(t *testing.T) {
 	}
 }
 
+func TestHigherOrderBuiltinFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"map([1, 2, 3], (x) => x * 2)", "[2, 4, 6]"},
+		{"map([], (x) => x * 2)", "[]"},
+		{"map([[1], [2, 3]], len)", "[1, 2]"},
+		{"map(#{1, 2}, (x) => x + 1)", "[2, 3]"},
+		{"filter([1, 2, 3, 4], (x) => x > 2)", "[3, 4]"},
+		{"reduce([1, 2, 3], (acc, x) => acc + x, 10)", "16"},
+		{"reduce([], (acc, x) => acc + x, 10)", "10"},
+		{"sortBy([3, 1, 2], (a, b) => a < b)", "[1, 2, 3]"},
+		{"sortBy([3, 1, 2], (a, b) => a > b)", "[3, 2, 1]"},
+		{"let xs = [2, 1]; sortBy(xs, (a, b) => a < b); xs", "[2, 1]"},
+		{"any([1, 2, 3], (x) => x > 2)", "true"},
+		{"any([], (x) => true)", "false"},
+		{"all([1, 2, 3], (x) => x > 0)", "true"},
+		{"all([1, 2, 3], (x) => x > 1)", "false"},
+		{"find([1, 2, 3], (x) => x > 1)", "2"},
+		{"find([1, 2, 3], (x) => x > 3)", "null"},
+		{`zip([1, 2, 3], ["a", "b"])`, "[[1, a], [2, b]]"},
+		{"range(3)", "[0, 1, 2]"},
+		{"range(2, 5)", "[2, 3, 4]"},
+		{"range(10, 0, -4)", "[10, 6, 2]"},
+		{"range(0)", "[]"},
+		{"range(5) |> map((x) => x * x) |> filter((x) => x > 3) |> reduce((acc, x) => acc + x, 0)", "29"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("input=%q evaluated to nil", tt.input)
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestHigherOrderBuiltinErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"map(1, (x) => x)", "argument to `map` not supported, got INTEGER"},
+		{"filter([1], 1)", "argument to `filter` not supported, expected Function, got INTEGER"},
+		{"map([1])", "Call Arguments and function defined parameters size mismatch.\n Expected 2 arguments but got 1 parameter(s)"},
+		{"map([1, 2], (x) => missing)", "Identifier not Found: missing"},
+		{"filter([1, 2], (x, y) => x)", "Call Arguments and function defined parameters size mismatch.\n Expected 2 arguments but got 1 parameter(s)"},
+		{"reduce([1, 2], (acc, x) => acc + x, \"a\")", "type mismatch: STRING + INTEGER"},
+		{"sortBy([2, 1], (a, b) => missing)", "Identifier not Found: missing"},
+		{"any([1], (x) => missing)", "Identifier not Found: missing"},
+		{"all([1], (x) => missing)", "Identifier not Found: missing"},
+		{"find([1], (x) => missing)", "Identifier not Found: missing"},
+		{"zip([1], 2)", "argument to `zip` not supported, got INTEGER"},
+		{"range(1, 2, 0)", "argument to `range` not supported, step cannot be 0"},
+		{"range(\"a\")", "argument to `range` not supported, got STRING"},
+		{"doWhile(fun() { missing })", "Identifier not Found: missing"},
+		{"doWhile(fun() { 1 })", "arguments to `doWhile` not supported, expected Function to return Boolean, got INTEGER"},
+		{"doWhile()", "Call Arguments and function defined parameters size mismatch.\n Expected 1 arguments but got 0 parameter(s)"},
+	}
+	for _, tt := range tests {
+		testErrorObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
 func TestArrayLiterals(t *testing.T) {
 	input := "[1, 2 * 2, 3 + 3]"
 	evaluated := testEval(input)
@@ -169,6 +534,46 @@ func TestArrayLiterals(t *testing.T) {
 	testIntegerObject(t, result.Elements[2], 6)
 }
 
+func TestSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2, 3, 4][1:3]", "[2, 3]"},
+		{"[1, 2, 3, 4][:2]", "[1, 2]"},
+		{"[1, 2, 3, 4][2:]", "[3, 4]"},
+		{"[1, 2, 3, 4][:]", "[1, 2, 3, 4]"},
+		{"[1, 2, 3, 4][-2:]", "[3, 4]"},
+		{"[1, 2, 3, 4][:-1]", "[1, 2, 3]"},
+		{"[1, 2, 3, 4][1:10]", "[2, 3, 4]"},
+		{"[1, 2, 3, 4][-10:2]", "[1, 2]"},
+		{"[1, 2, 3, 4][3:1]", "[]"},
+		{"let i = 1; [1, 2, 3, 4][i + 1:]", "[3, 4]"},
+		{"let xs = [1, 2, 3]; let ys = xs[:]; ys =&= xs", "false"},
+		{`"héllo"[1:3]`, "él"},
+		{`"héllo"[:-2]`, "hél"},
+		{`"héllo"[10:]`, ""},
+		{`1[1:]`, "expecting Array or String Type but got INTEGER"},
+		{`[1, 2][:"a"]`, "expecting Integer Type but got STRING"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("input=%q evaluated to nil", tt.input)
+			continue
+		}
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("input=%q, expected=%q, got error %q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
 func TestArrayIndexExpressions(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -212,6 +617,14 @@ func TestArrayIndexExpressions(t *testing.T) {
 		},
 		{
 			"[1, 2, 3][-1]",
+			3,
+		},
+		{
+			"[1, 2, 3][-3]",
+			1,
+		},
+		{
+			"[1, 2, 3][-4]",
 			nil,
 		},
 	}
@@ -241,23 +654,386 @@ func TestHashLiterals(t *testing.T) {
 	if !ok {
 		t.Fatalf("Eval didn't return Hash. got=%T (%+v)", evaluated, evaluated)
 	}
-	expected := map[object.HashKey]int64{
-		(&object.String{Value: "one"}).HashKey():   1,
-		(&object.String{Value: "two"}).HashKey():   2,
-		(&object.String{Value: "three"}).HashKey(): 3,
-		(&object.Integer{Value: 4}).HashKey():      4,
-		evaluator.TRUE.HashKey():                   5,
-		evaluator.FALSE.HashKey():                  6,
+	expected := map[object.Hashable]int64{
+		&object.String{Value: "one"}:   1,
+		&object.String{Value: "two"}:   2,
+		&object.String{Value: "three"}: 3,
+		&object.Integer{Value: 4}:      4,
+		evaluator.TRUE:                 5,
+		evaluator.FALSE:                6,
 	}
-	if len(result.Pairs) != len(expected) {
-		t.Fatalf("Hash has wrong num of pairs. got=%d", len(result.Pairs))
+	if result.Len() != len(expected) {
+		t.Fatalf("Hash has wrong num of pairs. got=%d", result.Len())
 	}
 	for expectedKey, expectedValue := range expected {
-		pair, ok := result.Pairs[expectedKey]
+		value, ok := result.Get(expectedKey)
 		if !ok {
-			t.Errorf("no pair for given key in Pairs")
+			t.Errorf("no pair for given key %s in Pairs", expectedKey.Inspect())
+			continue
+		}
+		testIntegerObject(t, value, expectedValue)
+	}
+}
+
+func TestHashInsertionOrder(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{}`, `{}`},
+		{`{"z": 1, "y": 2, "x": 3, 4: 4, true: 5, "a": 6}`, `{z : 1, y : 2, x : 3, 4 : 4, true : 5, a : 6}`},
+		{`{"b": 1, "a": 2, "b": 3}`, `{b : 3, a : 2}`},
+		{`let h = {"c": 3, "b": 2, "a": 1}; h`, `{c : 3, b : 2, a : 1}`},
+		{`{[1, 2]: 5, [1, 2]: 6, {"a": 1}: 7}`, `{[1, 2] : 6, {a : 1} : 7}`},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		result, ok := evaluated.(*object.Hash)
+		if !ok {
+			t.Fatalf("Eval didn't return Hash. got=%T (%+v)", evaluated, evaluated)
+		}
+		if result.Inspect() != tt.expected {
+			t.Errorf("Hash has wrong order. expected=%q, got=%q", tt.expected, result.Inspect())
+		}
+	}
+}
+
+func TestCompositeHashKeys(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let k = [1, 2]; let h = {[1, 2]: 5}; h[k]`, 5},
+		{`let k = [2, 1]; let h = {[1, 2]: 5}; h[k]`, nil},
+		{`let k = [[1], "a"]; let h = {[[1], "a"]: 5}; h[k]`, 5},
+		{`let k = {"b": 2, "a": 1}; let h = {{"a": 1, "b": 2}: 5}; h[k]`, 5},
+		{`let k = {"a": [1]}; let h = {{"a": [1]}: 5}; h[k]`, 5},
+		{`let k = [1, 2]; let h = {k: 5}; k = [3]; let j = [1, 2]; h[j]`, 5},
+		{`let k = [1, 2]; let h = {k: 5}; k = [3]; h[k]`, nil},
+		{`let k = "foo"; let h = {k: 1}; k = "bar"; h["foo"]`, 1},
+		{`let k = "foo"; let h = {k: 1}; k = "bar"; h["bar"]`, nil},
+		{`let k = "foo"; let h = {k: 1}; k = "bar"; h`, `{foo : 1}`},
+		{`let k = 1; let h = {k: 1}; k = 2; [h, h[1]]`, `[{1 : 1}, 1]`},
+		{`let h = {"foo": 1}; let k =& head(keys(h)); k = "bar"; [h, h["foo"]]`, `[{foo : 1}, 1]`},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if evaluated.Inspect() != expected {
+				t.Errorf("input=%q, expected=%q, got=%q", tt.input, expected, evaluated.Inspect())
+			}
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestValueEquality(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`[1, [2, 3]] =*= [1, [2, 3]]`, true},
+		{`[1, 2] =*= [2, 1]`, false},
+		{`[1, 2] =*= [1, 2, 3]`, false},
+		{`{"a": 1, "b": 2} =*= {"b": 2, "a": 1}`, true},
+		{`{"a": 1, "b": 2} =*= {"a": 1, "b": 3}`, false},
+		{`{"a": [1]} !*= {"a": [1]}`, false},
+		{`[1] =*= {1: 1}`, false},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestCyclicValues(t *testing.T) {
+	cyclic := `let h = {"a": 1}; h.me =& h; let g = {"a": 1}; g.me =& g; `
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{cyclic + `[h =*= h, h =*= g, h !*= g]`, `[true, true, false]`},
+		{cyclic + `let k = {"a": 2}; k.me =& k; h =*= k`, `false`},
+		{cyclic + `let k = {"a": 1}; let j = {"a": 1}; k.me =& j; j.me =& k; h =*= k`, `false`},
+		{cyclic + `let m = {h: 1}; [m[h], m[g], len(m)]`, `[1, 1, 1]`},
+		{cyclic + `let s = #{h, g}; [len(s), has(s, h), has(s, {"a": 1})]`, `[1, true, false]`},
+		{cyclic + `h`, `{a : 1, me : {...}}`},
+		{cyclic + `{h: 1}`, `{{a : 1, me : {...}} : 1}`},
+		{cyclic + `#{h}`, `#{{a : 1, me : {...}}}`},
+		{cyclic + `let a = [h, h]; a`, `[{a : 1, me : {...}}, {a : 1, me : {...}}]`},
+		{cyclic + `let k = {"a": 2}; k.arr = []; k.arr =& [k]; k`, `{a : 2, arr : [{...}]}`},
+		{cyclic + `"${h}"`, `{a : 1, me : {...}}`},
+		{`struct N { next }; let n = N(0); n.next =& n; n`, `N{next: N{...}}`},
+		{`struct N { next }; let n = N(0); n.next =& n; let m = N(0); m.next =& m; [n =*= m, n =*= N(0)]`, `[true, false]`},
+		{`struct N { next }; let n = N(0); n.next =& n; let m = N(0); m.next =& m; let s = #{n, m}; [len(s), has(s, m), s]`, `[1, true, #{N{next: N{...}}}]`},
+		{`struct N { next }; let n = N(0); n.next =& n; {n: 1}`, `{N{next: N{...}} : 1}`},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestSets(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`#{}`, `#{}`},
+		{`#{3, 1, 2, 1}`, `#{3, 1, 2}`},
+		{`#{[1, 2], [1, 2], {"a": 1}}`, `#{[1, 2], {a : 1}}`},
+		{`#{1, 2} | #{2, 3}`, `#{1, 2, 3}`},
+		{`#{1, 2, 3} & #{3, 2, 5}`, `#{2, 3}`},
+		{`#{1, 2, 3} - #{2}`, `#{1, 3}`},
+		{`set([1, "a", 1, "a"])`, `#{1, a}`},
+		{`toArray(#{2, 1, 2})`, `[2, 1]`},
+		{`len(#{1, 2, 2})`, `2`},
+		{`isEmpty(#{})`, `true`},
+		{`has(#{1, [2]}, [2])`, `true`},
+		{`has(#{1, 2}, 3)`, `false`},
+		{`#{1, 2} =*= #{2, 1}`, `true`},
+		{`#{1, 2} =*= #{1}`, `false`},
+		{`#{#{1, 2}, #{2, 1}}`, `#{#{1, 2}}`},
+		{`let i = 1; let s = #{i}; i = 2; [s, has(s, 1), has(s, 2)]`, `[#{1}, true, false]`},
+		{`let k = "a"; let s = #{k}; k = "b"; s =*= #{"a"}`, `true`},
+		{`let s = #{1}; let m =& head(toArray(s)); m = 2; [s, has(s, 1)]`, `[#{1}, true]`},
+		{`#{fun(x) { x }}`, `This element is not Hashable : fun(x) {` + "\nx\n}"},
+		{`#{1} + #{2}`, `unknown operator: SET + SET`},
+		{`has([1], 1)`, "argument to `has` not supported, got ARRAY"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestStructs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"struct Point { x, y }; Point", "struct Point { x, y }"},
+		{"struct Point { x, y }; Point(1, 2)", "Point{x: 1, y: 2}"},
+		{"struct Point { x, y }; let p = Point(1, [2]); p.y", "[2]"},
+		{"struct Point { x, y }; let p = Point(1, 2); p.x = 5; p", "Point{x: 5, y: 2}"},
+		{"let p = Point(1, 2); struct Point { x, y }; p.x", "1"},
+		{"struct Point { x, y }; let p = Point(1, 2); let q = p; q.x = 3; p.x", "1"},
+		{"struct Point { x, y }; let p = Point(1, 2); let q =& p; q.x = 3; p.x", "3"},
+		{"struct Point { x, y }; let a = [1]; let p = Point(0, 0); p.x = a; p.x =&= a", "false"},
+		{"struct Point { x, y }; let a = [1]; let p = Point(0, 0); p.x =& a; p.x =&= a", "true"},
+		{"struct Point { x, y }; Point(1, [2]) =*= Point(1, [2])", "true"},
+		{"struct Point { x, y }; Point(1, 2) =*= Point(2, 1)", "false"},
+		{"struct A { x }; struct B { x }; A(1) =*= B(1)", "false"},
+		{"struct Point { x, y }; #{Point(1, 2), Point(1, 2), Point(2, 1)}", "#{Point{x: 1, y: 2}, Point{x: 2, y: 1}}"},
+		{"struct Point { x, y }; type(Point(1, 2))", "Point"},
+		{"struct Point { x, y }; type(Point)", "Function"},
+		{"struct Point { x, y }; isFunction(Point)", "true"},
+		{"struct Point { x, y }; let p = freeze(Point([1], 2)); isFrozen(p.x)", "true"},
+		{"struct Point { x, y }; let p = freeze(Point(1, 2)); p.x = 3;", "cannot mutate frozen Point: p.x"},
+		{"struct Point { x, y }; Point(1, 2).z", "Point has no field z"},
+		{"struct Point { x, y }; let p = Point(1, 2); p.z = 1;", "Point has no field z"},
+		{"struct Point { x, y }; Point(1)", "Call Arguments and function defined parameters size mismatch.\n Expected 2 arguments but got 1 parameter(s)"},
+		{"let a = [1]; a.x = 1;", "expecting Struct or Hash Type but got ARRAY"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestTaggedUnions(t *testing.T) {
+	shapes := "type Shape = Circle(r) | Rect(w, h) | Empty; "
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{shapes + "Circle(2)", "Circle{r: 2}"},
+		{shapes + "Empty", "Empty"},
+		{shapes + "Rect", "Shape.Rect(w, h)"},
+		{shapes + "Rect(2, 3).h", "3"},
+		{shapes + "let c = Circle(1); c.r = 5; c", "Circle{r: 5}"},
+		{shapes + "tag(Rect(2, 3))", "Rect"},
+		{shapes + "tag(Empty)", "Empty"},
+		{shapes + "type(Circle(1))", "Shape"},
+		{shapes + "type(Empty)", "Shape"},
+		{"struct Point { x }; tag(Point(1))", "Point"},
+		{shapes + "Circle(1) =*= Circle(1)", "true"},
+		{shapes + "Circle(1) =*= Rect(1, 1)", "false"},
+		{shapes + "Empty =*= Empty", "true"},
+		{shapes + "#{Empty, Circle(1), Empty, Circle(1)}", "#{Empty, Circle{r: 1}}"},
+		{shapes + "map([Circle(1), Empty, Rect(1, 2)], tag)", "[Circle, Empty, Rect]"},
+		{"let area = fun(s) { if (tag(s) =*= \"Rect\") { return s.w * s.h; }; 0 }; type Shape = Rect(w, h) | Empty; area(Rect(2, 3)) + area(Empty)", "6"},
+		{"let type = 5; type", "5"},
+		{shapes + "Circle(1).w", "Circle has no field w"},
+		{shapes + "Rect(1)", "Call Arguments and function defined parameters size mismatch.\n Expected 2 arguments but got 1 parameter(s)"},
+		{"tag(1)", "argument to `tag` not supported, got INTEGER"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestMethods(t *testing.T) {
+	dog := `let dog = {"name": "Rex", "speak": fun(sound) { "${self.name} says ${sound}" }}; `
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{dog + `dog."speak"("woof")`, "Rex says woof"},
+		{dog + `dog.speak("woof")`, "Rex says woof"},
+		{dog + `"woof" |> dog.speak`, "Rex says woof"},
+		{dog + `"woof" |> dog.speak()`, "Rex says woof"},
+		{dog + `let cat = {"name": "Tom", "speak": dog.speak}; cat.speak("meow")`, "Tom says meow"},
+		{dog + `let self = 1; dog.speak("woof")`, "Rex says woof"},
+		{`let obj = {"get": fun() { fun() { self } }}; obj.get()() =&= obj`, "true"},
+		{`let obj = {"len": len}; obj.len("abc")`, "3"},
+		{"struct Counter { count, inc }; let c = Counter(0, fun() { self.count = self.count + 1; self.count }); c.inc(); c.inc()", "2"},
+		{"struct Counter { count, inc }; let c = Counter(0, fun() { self.count = self.count + 1; }); c.inc(); c.count", "1"},
+		{dog + `let speak = dog.speak; speak("woof")`, "Identifier not Found: self\n\tat <anonymous>"},
+		{`let obj = {"x": 1}; obj.x()`, "Is not Callable (not a recognized function): INTEGER"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestHashIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`{"foo": 5}["foo"]`, 5},
+		{`{"foo": 5}["bar"]`, nil},
+		{`let key = "foo"; {"foo": 5}[key]`, 5},
+		{`{1: 5}[2 - 1]`, 5},
+		{`{[1, 2]: 5}[[1, 2]]`, 5},
+		{`let h = {"a": {"b": 5}}; h["a"]["b"]`, 5},
+		{`{"a": 5}[[1]]`, nil},
+		{`{"a": 5}[fun() {}]`, "expecting Hashable Type but got FUNCTION"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testErrorObject(t, evaluated, expected)
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestHashBuiltinFunctions(t *testing.T) {
+	h := `let h = {"a": 1, "b": [2], 3: "c"}; `
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{h + `keys(h)`, `[a, b, 3]`},
+		{h + `values(h)`, `[1, [2], c]`},
+		{h + `entries(h)`, `[[a, 1], [b, [2]], [3, c]]`},
+		{`keys({})`, `[]`},
+		{h + `fromEntries(entries(h)) =*= h`, `true`},
+		{`fromEntries([["a", 1], ["b", 2], ["a", 3]])`, `{a : 3, b : 2}`},
+		{h + `has(h, "a")`, `true`},
+		{h + `has(h, "z")`, `false`},
+		{h + `has(h, [1])`, `false`},
+		{h + `delete(h, "b")`, `{a : 1, 3 : c}`},
+		{h + `delete(h, "z")`, `{a : 1, b : [2], 3 : c}`},
+		{h + `let d = delete(h, "a"); h`, `{a : 1, b : [2], 3 : c}`},
+		{`merge({"a": 1, "b": 2}, {"b": 3}, {"c": 4})`, `{a : 1, b : 3, c : 4}`},
+		{`merge({})`, `{}`},
+		{h + `get(h, "b")`, `[2]`},
+		{h + `get(h, 3)`, `c`},
+		{h + `h["z"]`, `null`},
+		{h + `len(h)`, `3`},
+		{`isEmpty({})`, `true`},
+		{h + `get(h, "z")`, `key not found: z`},
+		{h + `get(h, fun() {})`, `expecting Hashable Type but got FUNCTION`},
+		{`keys([1])`, "argument to `keys` not supported, got ARRAY"},
+		{`merge({}, [1])`, "argument to `merge` not supported, got ARRAY"},
+		{`merge()`, "Call Arguments and function defined parameters size mismatch.\n Expected at least 1 arguments but got 0 parameter(s)"},
+		{`delete([1], 0)`, "argument to `delete` not supported, got ARRAY"},
+		{`fromEntries([["a"]])`, "`fromEntries` expects [key, value] pairs, got [a]"},
+		{`fromEntries([[fun() {}, 1]])`, "This key is not Hashable : fun() {\n\n}"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestNullSafeOperators(t *testing.T) {
+	config := `let none = {}.missing; let config = {"server": {"port": 80, "name": none}, "greet": fun() { "hi" }}; `
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{config + `config?.server?.port`, `80`},
+		{config + `config?.client?.port`, `null`},
+		{config + `config.client?.port?.number`, `null`},
+		{`{}.a?.b`, `null`},
+		{config + `config.client?.port ?? 8080`, `8080`},
+		{config + `config.server.port ?? 8080`, `80`},
+		{config + `config.server.name ?? "default"`, `default`},
+		{`false ?? true`, `false`},
+		{`0 ?? 1`, `0`},
+		{`{}.a ?? {}.b ?? 3`, `3`},
+		{`1 ?? undefinedName`, `1`},
+		{config + `config?.greet()`, `hi`},
+		{config + `config.client?.greet()`, `null`},
+		{config + `config?.missing()`, `null`},
+		{config + `1 |> config.client?.greet`, `null`},
+		{`struct Node { next }; let n = Node({}.missing); n.next?.next`, `null`},
+		{config + `config.client.port`, `expecting Hash Type but got NULL`},
+		{`1?.a`, `expecting Hash Type but got INTEGER`},
+		{`{}.a ?? undefinedName`, `Identifier not Found: undefinedName`},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestHashFieldAssignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let h = {"a": 1}; h.b = 2; h`, "{a : 1, b : 2}"},
+		{`let h = {"a": 1}; h.a = [2]; h`, "{a : [2]}"},
+		{`let a = [1]; let h = {}; h.a = a; h.a =&= a`, "false"},
+		{`let a = [1]; let h = {}; h.a =& a; h.a =&= a`, "true"},
+		{`let h = {"a": 1}; let g = h; g.a = 2; h.a`, "1"},
+		{`let h = {"a": 1}; let g =& h; g.a = 2; h.a`, "2"},
+		{`let h = freeze({"a": 1}); h.a = 2;`, "cannot mutate frozen HASH: h.a"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
 		}
-		testIntegerObject(t, pair.Value, expectedValue)
 	}
 }
 
@@ -276,6 +1052,26 @@ func TestDotExpressions(t *testing.T) {
 		},
 		{
 			`let key = "foo"; {"foo": 5}.key`,
+			nil,
+		},
+		{
+			`let foo = "bar"; {"foo": 5}.foo`,
+			5,
+		},
+		{
+			`{"a": {"b": 5}}.a.b`,
+			5,
+		},
+		{
+			`{"a": 2}.a + {"b": 3}.b`,
+			5,
+		},
+		{
+			`let h = {"a": 1}; h.a = 5; h.a`,
+			5,
+		},
+		{
+			`let h = {}; h.a = 5; h."a"`,
 			5,
 		},
 		{
@@ -294,6 +1090,10 @@ func TestDotExpressions(t *testing.T) {
 			`{false: 5}.false`,
 			5,
 		},
+		{
+			`let k = "foo"; let h = {k: 1}; k = "bar"; {k: 5}."bar"`,
+			5,
+		},
 	}
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
@@ -390,6 +1190,177 @@ func TestLetStatementEval(t *testing.T) {
 	}
 }
 
+func TestConstStatementEval(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"const a = 5; a;", 5},
+		{"const a = 5; let f = fun(a) { a }; f(3);", 3},
+		{"const a = 5; a = 6;", "cannot reassign constant: a"},
+		{"const a = 5; a =& 6;", "cannot reassign constant: a"},
+		{"const a = 5; let a = 6;", "cannot reassign constant: a"},
+		{"const a = 5; const a = 6;", "cannot reassign constant: a"},
+		{"const a = 5; let f = fun() { a = 6; }; f();", "cannot reassign constant: a"},
+		{"const f = 1; fun f() {}", "cannot reassign constant: f"},
+		{"const P = 1; struct P { x }", "cannot reassign constant: P"},
+		{"const Empty = 1; type Shape = Circle(r) | Empty", "cannot reassign constant: Empty"},
+		{"const f = 1; if (true) { fun f() { 2 }; f() }", 2},
+		{"fun f() { 2 }; const f = 1; f", 1},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testErrorObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestConstRedeclaredByLaterProgram(t *testing.T) {
+	env := object.NewEnvironment()
+	evaluator.Eval(parser.New(tokenizer.New("const f = 1;")).ParseProgram(), env)
+	inputs := []string{"fun f() {}", "struct f { x }", "type T = f"}
+	for _, input := range inputs {
+		evaluated := evaluator.Eval(parser.New(tokenizer.New(input)).ParseProgram(), env)
+		testErrorObject(t, evaluated, "cannot reassign constant: f")
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"isFrozen([1])", false},
+		{"isFrozen(freeze([1]))", true},
+		{"let a = [1, [2]]; freeze(a); isFrozen(a)", true},
+		{"let a = freeze([1, [2]]); isFrozen(a[1])", true},
+		{`let a = freeze({"k": [1]}); isFrozen(a."k")`, true},
+		{"let a = freeze(#{[1]}); isFrozen(head(toArray(a)))", true},
+		{"isFrozen(freeze(5))", false},
+		{"let a = freeze([1]); a = [2];", "cannot mutate frozen ARRAY: a"},
+		{"let a = freeze([1, [2]]); let b =& a[1]; b = [3];", "cannot mutate frozen ARRAY: b"},
+		{`let a = freeze({"k": 1}); a = {};`, "cannot mutate frozen HASH: a"},
+		{"let a = freeze(#{1}); a = #{};", "cannot mutate frozen SET: a"},
+		{"let a = freeze([1]); a =& [2]; isFrozen(a)", false},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			testErrorObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let a = [[1], {"k": [2]}]; let b = a; b =&= a`, false},
+		{`let a = [[1], {"k": [2]}]; let b = a; b[0] =&= a[0]`, false},
+		{`let a = [[1], {"k": [2]}]; let b = a; b[1] =&= a[1]`, false},
+		{`let a = [[1], {"k": [2]}]; let b = a; (b[1]."k") =&= (a[1]."k")`, false},
+		{`let a = [[1], {"k": [2]}]; let b =* a; get((b[1]."k"), 0) =&= get((a[1]."k"), 0)`, false},
+		{`let a = [[1], {"k": [2]}]; let b = a; b =*= a`, true},
+		{`let a = [[1], {"k": [2]}]; let b =& a; b[0] =&= a[0]`, true},
+		{`let a = [[1]]; let b = [[0]]; b = a; b[0] =&= a[0]`, false},
+		{`let a = [[1], {"k": [2]}]; let b = clone(a); (b[1]."k") =&= (a[1]."k")`, false},
+		{`let a = [[1], {"k": [2]}]; let b = clone(a); b =*= a`, true},
+		{`let a = #{[1]}; let b = clone(a); b =&= a`, false},
+		{`let a = freeze([[1]]); let b = a; b =&= a`, true},
+		{`let a = true; a = false; true`, true},
+	}
+	for _, tt := range tests {
+		testBooleanObject(t, testEval(tt.input), tt.expected.(bool))
+	}
+}
+
+func TestDeepCopyMutation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let a = [[1]]; let b = a; let inner =& b[0]; inner = [5]; a`, `[[1]]`},
+		{`let a = [[1]]; let b = a; let inner =& b[0]; inner = [5]; b`, `[[5]]`},
+		{`let a = [[1]]; let b =& a; let inner =& b[0]; inner = [5]; a`, `[[5]]`},
+		{`let a = {"k": [1]}; let b = clone(a); let inner =& b."k"; inner = [2]; [a, b]`, `[{k : [1]}, {k : [2]}]`},
+		{`let a = [1]; a = insert(a, a, 0); a`, `[[1]]`},
+		{`let a = 1; a = "one"; a`, `one`},
+		{`let a = 1; let b =& a; a = 2; b`, `2`},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestScoping(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let x = 1; let f = fun() { x = 2; }; f(); x", 2},
+		{"let x = 1; let f = fun() { x =& 2; }; f(); x", 2},
+		{"let x = 1; let f = fun() { x =* 2; }; f(); x", 2},
+		{"let x = 1; let f = fun() { let x = 2; x }; f(); x", 1},
+		{"let x = 1; let f = fun() { let x = 2; x }; f()", 2},
+		{"let x = 1; let f = fun(x) { x = 3; x }; f(2) + x", 4},
+		{"let newCounter = fun() { let c = 0; fun() { c = c + 1; c } }; let next = newCounter(); next(); next(); next()", 3},
+		{"let a = 1; let b =& a; let a = 2; b", 1},
+		{"let a = 1; let b =& a; a = 2; b", 2},
+		{"y = 5;", "Identifier not declared: y"},
+		{"y =& 5;", "Identifier not declared: y"},
+		{"let f = fun() { z = 1; }; f();", "Identifier not declared: z"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testErrorObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestBlockScoping(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let x = 1; if (true) { let x = 2; }; x", 1},
+		{"let x = 1; if (true) { let x = 2; x }", 2},
+		{"let x = 1; if (false) { 0 } else { let x = 3; }; x", 1},
+		{"let x = 1; if (true) { x = 2; }; x", 2},
+		{"if (true) { let y = 2; }; y", "Identifier not Found: y"},
+		{"let i = 0; while (i < 3) { let y = i; i = i + 1; }; y", "Identifier not Found: y"},
+		{"let i = 0; let sum = 0; while (i < 3) { let double = i * 2; sum = sum + double; i = i + 1; }; sum", 6},
+		{"let i = 0; while (i < 3) { i = i + 1; let i = 10; }; i", 3},
+		{"let f = fun() { let i = 0; while (i < 3) { let x = i; i = i + 1; }; x }; f()", "Identifier not Found: x"},
+		{"let f = fun() { let i = 0; while (true) { i = i + 1; if (i > 4) { return i; } } }; f()", 5},
+		{"let i = 0; while (i < 3) { i = i + true; }", "type mismatch: INTEGER + BOOLEAN"},
+		{"while (x) { 1 }", "Identifier not Found: x"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testErrorObject(t, evaluated, expected)
+		}
+	}
+}
+
 func TestFunctionObject(t *testing.T) {
 	input := "fun(x) { x + 2; };"
 	evaluated := testEval(input)
@@ -427,6 +1398,177 @@ func TestFunctionApplication(t *testing.T) {
 	}
 }
 
+func TestFunctionDeclarations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"fun double(x) { x * 2 }; double(4)", 8},
+		{"double(4); fun double(x) { x * 2 }", 8},
+		{"let a = double(4); fun double(x) { x * 2 }; a", 8},
+		{`
+		fun isEven(n) { if (n =*= 0) { return true; }; return isOdd(n - 1); }
+		fun isOdd(n) { if (n =*= 0) { return false; }; return isEven(n - 1); }
+		if (isEven(10) & isOdd(7)) { 1 } else { 0 }`, 1},
+		{"fun fact(n) { if (n < 2) { return 1; }; n * fact(n - 1) }; fact(5)", 120},
+		{"let f = fun() { let r = g(); fun g() { 3 }; r }; f()", 3},
+		{"let f = fun() { fun g() { 3 }; 0 }; f(); g()", "Identifier not Found: g"},
+		{"fun g() { 1 }; let f = fun() { fun g() { 2 }; g() }; f() + g()", 3},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testErrorObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestNamedFunctionObject(t *testing.T) {
+	evaluated := testEval("fun add(x, y) { x + y }; add")
+	fun, ok := evaluated.(*object.Function)
+	if !ok {
+		t.Fatalf("object is not Function. got=%T (%+v)", evaluated, evaluated)
+	}
+	if fun.Name != "add" {
+		t.Errorf("function name is not 'add'. got=%q", fun.Name)
+	}
+	expected := "fun add(x, y) {\n(x + y)\n}"
+	if fun.Inspect() != expected {
+		t.Errorf("function Inspect is not %q. got=%q", expected, fun.Inspect())
+	}
+}
+
+func TestErrorStackTrace(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedStack []string
+	}{
+		{"1 + true", []string{}},
+		{"fun f() { 1 + true }; f()", []string{"f"}},
+		{"fun outer() { inner() }; fun inner() { 1 + true }; outer()", []string{"inner", "outer"}},
+		{"let f = fun() { 1 + true }; fun g() { f() }; g()", []string{"<anonymous>", "g"}},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if len(errObj.Stack) != len(tt.expectedStack) {
+			t.Errorf("wrong stack. expected=%v, got=%v", tt.expectedStack, errObj.Stack)
+			continue
+		}
+		for i, frame := range tt.expectedStack {
+			if errObj.Stack[i] != frame {
+				t.Errorf("wrong stack frame %d. expected=%q, got=%q", i, frame, errObj.Stack[i])
+			}
+		}
+	}
+	errObj := testEval("fun outer() { inner() }; fun inner() { 1 + true }; outer()")
+	expected := "type mismatch: INTEGER + BOOLEAN\n\tat inner\n\tat outer"
+	if errObj.Inspect() != expected {
+		t.Errorf("error Inspect is not %q. got=%q", expected, errObj.Inspect())
+	}
+}
+
+func TestArrowFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let lt = (x, y) => x < y; lt(1, 2)", true},
+		{"let lt = (x, y) => x < y; lt(2, 1)", false},
+		{"let double = (x) => x * 2; double(4)", 8},
+		{"(() => 5)()", 5},
+		{"let adder = (x) => (y) => x + y; adder(2)(3)", 5},
+		{"let apply = fun(f, x) { f(x) }; apply((x) => x + 1, 1)", 2},
+		{"let x = 3; (x) * 2", 6},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestPipeExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let inc = fun(x) { x + 1 }; 1 |> inc", 2},
+		{"let inc = fun(x) { x + 1 }; 1 |> inc |> inc", 3},
+		{"let sub = fun(x, y) { x - y }; 10 |> sub(3)", 7},
+		{"let sub = fun(x, y) { x - y }; 1 + 9 |> sub(3) |> sub(2)", 5},
+		{"[1, 2] |> append(3) |> len", 3},
+		{"5 |> (x) => x * 2", 10},
+		{"let adder = fun(x) { fun(y) { x + y } }; 1 |> adder(2)()", 3},
+		{"1 |> 2", "Is not Callable (not a recognized function): INTEGER"},
+		{"1 |> missing(2)", "Identifier not Found: missing"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			testErrorObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestExceptions(t *testing.T) {
+	risky := `fun risky(x) { if (x > 1) { throw {"message": "too big", "type": "RangeError"}; }; x } fun outer(x) { risky(x) } `
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`try { 1 } catch (e) { 2 }`, `1`},
+		{`try { throw "boom"; 1 } catch (e) { e.message }`, `boom`},
+		{`try { throw "boom"; } catch (e) { e.type }`, `Error`},
+		{`try { throw [1, 2]; } catch (e) { e.value }`, `[1, 2]`},
+		{`try { throw 42; } catch (e) { e.message }`, `42`},
+		{risky + `try { outer(5) } catch (e) { [e.type, e.message, e.stack] }`, `[RangeError, too big, [risky, outer]]`},
+		{risky + `try { outer(1) } catch (e) { 0 }`, `1`},
+		{`try { 1 + "a" } catch (e) { [e.message, e.type, e.value] }`, `[type mismatch: INTEGER + STRING, Error, null]`},
+		{`try { len(1) } catch (e) { e.message }`, "argument to `len` not supported, got INTEGER"},
+		{`try { undefinedName } catch (e) { e.message }`, `Identifier not Found: undefinedName`},
+		{`const c = 1; try { c = 2; } catch (e) { e.message }`, `cannot reassign constant: c`},
+		{`let log = []; try { throw "x"; } catch (e) { log = append(log, "catch"); } finally { log = append(log, "finally"); }; log`, `[catch, finally]`},
+		{`let log = []; let r = try { 1 } finally { log = append(log, "finally"); }; [r, log]`, `[1, [finally]]`},
+		{`let f = fun() { try { return 1; } finally { 2 } }; f()`, `1`},
+		{`let f = fun() { try { return 1; } finally { return 2; } }; f()`, `2`},
+		{`let f = fun() { try { throw "a"; } finally { 2 } }; try { f() } catch (e) { e.message }`, `a`},
+		{`try { try { throw "inner"; } catch (e) { throw "outer: " + e.message; } } catch (e) { e.message }`, `outer: inner`},
+		{`try { try { throw {"message": "m", "type": "T"}; } catch (e) { throw e; } } catch (e) { [e.type, e.message, e.value, e.stack] }`, `[T, m, {message : m, type : T}, []]`},
+		{risky + `fun again(x) { try { outer(x) } catch (e) { throw e; } } try { again(5) } catch (e) { [e.type, e.message, e.stack] }`, `[RangeError, too big, [risky, outer, again]]`},
+		{`fun f() { 1 + "a" } try { try { f() } catch (e) { throw e; } } catch (e) { [e.message, e.value, e.stack] }`, `[type mismatch: INTEGER + STRING, null, [f]]`},
+		{`try { throw {"message": "m", "type": "T", "stack": [], "value": 7}; } catch (e) { e.value }`, `{message : m, type : T, stack : [], value : 7}`},
+		{`try { try { throw "a"; } catch (e) { throw {"message": e.message, "type": e.type, "stack": e.stack, "value": 7}; } } catch (e) { e.value }`, `{message : a, type : Error, stack : [], value : 7}`},
+		{`let e = 5; try { throw "x"; } catch (e) { 1 }; e`, `5`},
+		{`try { let x = 1; throw "x"; } catch (e) { x }`, `Identifier not Found: x`},
+		{`try { throw "x"; } catch (e) { 1 + "a" }`, `type mismatch: INTEGER + STRING`},
+		{`try { 1 } finally { throw "final"; }`, `final`},
+		{`throw "uncaught";`, `uncaught`},
+		{`throw {"message": "m", "type": "T"};`, `m`},
+		{`try {} catch (e) { 1 }`, `null`},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
 func TestClosures(t *testing.T) {
 	input := `
 	let newAdder = fun(x) {
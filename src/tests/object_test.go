@@ -20,3 +20,141 @@ func TestStringHashKey(t *testing.T) {
 		t.Errorf("strings with different content have same hash keys")
 	}
 }
+
+func TestStringHashKeyCache(t *testing.T) {
+	str := &object.String{Value: "Hello World"}
+	first := str.HashKey()
+	if str.HashKey() != first {
+		t.Errorf("cached hash key differs from first computed hash key")
+	}
+	if first != (&object.String{Value: "Hello World"}).HashKey() {
+		t.Errorf("cached hash key differs from freshly computed hash key")
+	}
+}
+
+func TestCompositeHashKey(t *testing.T) {
+	arr1 := &object.Array{Elements: []object.Object{&object.Integer{Value: 1}, &object.String{Value: "a"}}}
+	arr2 := &object.Array{Elements: []object.Object{&object.Integer{Value: 1}, &object.String{Value: "a"}}}
+	arr3 := &object.Array{Elements: []object.Object{&object.String{Value: "a"}, &object.Integer{Value: 1}}}
+	if arr1.HashKey() != arr2.HashKey() {
+		t.Errorf("arrays with same elements have different hash keys")
+	}
+	if arr1.HashKey() == arr3.HashKey() {
+		t.Errorf("arrays with different element order have same hash keys")
+	}
+
+	hash1 := object.NewHash()
+	hash1.Set(&object.String{Value: "x"}, &object.Integer{Value: 1})
+	hash1.Set(&object.String{Value: "y"}, arr1)
+	hash2 := object.NewHash()
+	hash2.Set(&object.String{Value: "y"}, arr2)
+	hash2.Set(&object.String{Value: "x"}, &object.Integer{Value: 1})
+	if hash1.HashKey() != hash2.HashKey() {
+		t.Errorf("hashes with same entries have different hash keys")
+	}
+	if !object.Equal(hash1, hash2) {
+		t.Errorf("hashes with same entries are not equal")
+	}
+}
+
+func TestDeepCopyCycles(t *testing.T) {
+	inner := &object.Array{Elements: []object.Object{&object.Integer{Value: 1}}}
+	arr := &object.Array{Elements: []object.Object{inner, nil}}
+	arr.Elements[1] = arr // Array containing itself
+
+	copied, ok := object.DeepCopy(arr).(*object.Array)
+	if !ok {
+		t.Fatalf("copy is not Array. got=%T", copied)
+	}
+	if copied == arr {
+		t.Errorf("copy is the original array")
+	}
+	if copied.Elements[1] != copied {
+		t.Errorf("cycle is not reproduced in copy")
+	}
+	if copied.Elements[0] == inner {
+		t.Errorf("nested array is shared with the original")
+	}
+	if !object.Equal(copied.Elements[0], inner) {
+		t.Errorf("nested array copy has different value")
+	}
+}
+
+func TestCyclicHashKeys(t *testing.T) {
+	newCycle := func(value int64) *object.Array {
+		arr := &object.Array{Elements: []object.Object{&object.Integer{Value: value}, nil}}
+		arr.Elements[1] = arr
+		return arr
+	}
+	arr1, arr2, arr3 := newCycle(1), newCycle(1), newCycle(2)
+	if arr1.HashKey() != arr2.HashKey() {
+		t.Errorf("cyclic arrays with same elements have different hash keys")
+	}
+	if !object.Equal(arr1, arr2) {
+		t.Errorf("cyclic arrays with same elements are not equal")
+	}
+	if object.Equal(arr1, arr3) {
+		t.Errorf("cyclic arrays with different elements are equal")
+	}
+
+	hash := object.NewHash()
+	hash.Set(arr1, &object.Integer{Value: 1})
+	if value, ok := hash.Get(arr2); !ok || value.(*object.Integer).Value != 1 {
+		t.Errorf("cyclic key not found by an equal cyclic key")
+	}
+	if _, ok := hash.Get(arr3); ok {
+		t.Errorf("cyclic key found by a different cyclic key")
+	}
+}
+
+// Key type whose instances all share the same HashKey
+type collidingKey struct {
+	name string
+}
+
+func (c *collidingKey) Type() object.ObjectType { return "COLLIDING" }
+func (c *collidingKey) Inspect() string         { return c.name }
+func (c *collidingKey) HashKey() object.HashKey {
+	return object.HashKey{Type: c.Type(), Value: 42}
+}
+
+func TestHashCollisions(t *testing.T) {
+	hash := object.NewHash()
+	foo := &collidingKey{name: "foo"}
+	bar := &collidingKey{name: "bar"}
+	hash.Set(foo, &object.Integer{Value: 1})
+	hash.Set(bar, &object.Integer{Value: 2})
+	if hash.Len() != 2 {
+		t.Fatalf("colliding keys overwrote each other. got=%d entries", hash.Len())
+	}
+	tests := []struct {
+		key      object.Hashable
+		expected int64
+	}{
+		{foo, 1},
+		{bar, 2},
+		{&collidingKey{name: "foo"}, 1},
+	}
+	for _, tt := range tests {
+		value, ok := hash.Get(tt.key)
+		if !ok {
+			t.Errorf("no value for key %s", tt.key.Inspect())
+			continue
+		}
+		if value.(*object.Integer).Value != tt.expected {
+			t.Errorf("wrong value for key %s. expected=%d, got=%d",
+				tt.key.Inspect(), tt.expected, value.(*object.Integer).Value)
+		}
+	}
+	if _, ok := hash.Get(&collidingKey{name: "baz"}); ok {
+		t.Errorf("found value for key that was never set")
+	}
+
+	hash.Set(&collidingKey{name: "foo"}, &object.Integer{Value: 3})
+	if hash.Len() != 2 {
+		t.Fatalf("overwriting key added an entry. got=%d entries", hash.Len())
+	}
+	if hash.Inspect() != "{foo : 3, bar : 2}" {
+		t.Errorf("wrong hash contents. got=%q", hash.Inspect())
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Youssef-Mak/baby-interpreter/pkg/ast"
 	"github.com/Youssef-Mak/baby-interpreter/pkg/parser"
+	"github.com/Youssef-Mak/baby-interpreter/pkg/token"
 	"github.com/Youssef-Mak/baby-interpreter/pkg/tokenizer"
 )
 
@@ -140,6 +141,60 @@ func TestStringLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestTemplateLiteralExpression(t *testing.T) {
+	input := `"sum: ${a + b}!";`
+	tok := tokenizer.New(input)
+	p := parser.New(tok)
+	program := p.ParseProgram()
+	checkErrors(t, p)
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	template, ok := stmt.Expression.(*ast.TemplateLiteral)
+	if !ok {
+		t.Fatalf("exp not *ast.TemplateLiteral. got=%T", stmt.Expression)
+	}
+	if len(template.Parts) != 3 {
+		t.Fatalf("template.Parts has wrong length. got=%d", len(template.Parts))
+	}
+	testStringLiteral(t, template.Parts[0], "sum: ")
+	testInfixExpression(t, template.Parts[1], "a", "+", "b")
+	testStringLiteral(t, template.Parts[2], "!")
+	if template.String() != "sum: ${(a + b)}!" {
+		t.Errorf("template.String() wrong. got=%q", template.String())
+	}
+}
+
+func TestTemplateLiteralErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a ${}"`, "In interpolation ${}: Empty interpolation in string"},
+		{`"a ${1 +}"`, "In interpolation ${1 +}: no prefix parse function for EOF was found"},
+		{`"a ${1 2}"`, "In interpolation ${1 2}: expected token [EOF], but got INT"},
+	}
+	for _, tt := range tests {
+		p := parser.New(tokenizer.New(tt.input))
+		p.ParseProgram()
+		errors := p.GetErrors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("input=%q, expected error %q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func testStringLiteral(t *testing.T, exp ast.Expression, value string) bool {
+	str, ok := exp.(*ast.StringLiteral)
+	if !ok {
+		t.Errorf("exp not *ast.StringLiteral. got=%T", exp)
+		return false
+	}
+	if str.Value != value {
+		t.Errorf("str.Value not %q. got=%q", value, str.Value)
+		return false
+	}
+	return true
+}
+
 func TestParsingArrayLiterals(t *testing.T) {
 	input := "[1, 2 * 2, 3 + 3]"
 	l := tokenizer.New(input)
@@ -159,6 +214,30 @@ func TestParsingArrayLiterals(t *testing.T) {
 	testInfixExpression(t, array.Elements[2], 3, "+", 3)
 }
 
+func TestParsingSetLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"#{}", "#{}"},
+		{"#{1}", "#{1}"},
+		{"#{1, 2 * 2, a}", "#{1, (2 * 2), a}"},
+	}
+	for _, tt := range tests {
+		l := tokenizer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkErrors(t, p)
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		if _, ok := stmt.Expression.(*ast.SetLiteral); !ok {
+			t.Fatalf("exp not ast.SetLiteral. got=%T", stmt.Expression)
+		}
+		if program.String() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+}
+
 func TestParsingIndexExpressions(t *testing.T) {
 	input := "myArray[1 + 1]"
 	l := tokenizer.New(input)
@@ -207,6 +286,32 @@ func TestParsingHashLiterals(t *testing.T) {
 	}
 }
 
+func TestParsingHashLiteralOrder(t *testing.T) {
+	input := `{"one": 1, "two": 2, "three": 3, "four": 4, "five": 5}`
+	l := tokenizer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	checkErrors(t, p)
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+	expected := []string{"one", "two", "three", "four", "five"}
+	if len(hash.Keys) != len(expected) {
+		t.Fatalf("hash.Keys has wrong length. got=%d", len(hash.Keys))
+	}
+	for i, key := range hash.Keys {
+		if key.String() != expected[i] {
+			t.Errorf("hash.Keys[%d] is not %q. got=%q", i, expected[i], key.String())
+		}
+	}
+	expectedString := "{one : 1, two : 2, three : 3, four : 4, five : 5}"
+	if hash.String() != expectedString {
+		t.Errorf("hash.String() is not %q. got=%q", expectedString, hash.String())
+	}
+}
+
 func TestParsingEmptyHashLiteral(t *testing.T) {
 	input := "{}"
 	l := tokenizer.New(input)
@@ -596,6 +701,46 @@ func TestFunctionLiteralParsing(t *testing.T) {
 	testInfixExpression(t, bodyStmt.Expression, "x", "+", "y")
 }
 
+func TestFunctionDeclarationParsing(t *testing.T) {
+	input := `fun add(x, y) { x + y; }; fun(x) { x };`
+	l := tokenizer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	checkErrors(t, p)
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			2, len(program.Statements))
+	}
+	decl, ok := program.Statements[0].(*ast.FunctionDeclaration)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.FunctionDeclaration. got=%T",
+			program.Statements[0])
+	}
+	if !testIdentifier(t, decl.Name, "add") {
+		return
+	}
+	if decl.Function.Name != "add" {
+		t.Errorf("function literal name is not 'add'. got=%q", decl.Function.Name)
+	}
+	if len(decl.Function.Parameters) != 2 {
+		t.Fatalf("function literal parameters wrong. want 2, got=%d\n",
+			len(decl.Function.Parameters))
+	}
+	testLiteralExpression(t, decl.Function.Parameters[0], "x")
+	testLiteralExpression(t, decl.Function.Parameters[1], "y")
+	if decl.String() != "fun add(x, y) (x + y)" {
+		t.Errorf("decl.String() wrong. got=%q", decl.String())
+	}
+	stmt, ok := program.Statements[1].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("program.Statements[1] is not ast.ExpressionStatement. got=%T",
+			program.Statements[1])
+	}
+	if _, ok := stmt.Expression.(*ast.FunctionLiteral); !ok {
+		t.Fatalf("stmt.Expression is not ast.FunctionLiteral. got=%T", stmt.Expression)
+	}
+}
+
 func TestFunctionParameterParsing(t *testing.T) {
 	tests := []struct {
 		input          string
@@ -622,6 +767,319 @@ func TestFunctionParameterParsing(t *testing.T) {
 	}
 }
 
+func TestArrowFunctionParsing(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedParams []string
+		expected       string
+	}{
+		{"(x, y) => x < y", []string{"x", "y"}, "fun(x, y) return (x < y);"},
+		{"(x) => x", []string{"x"}, "fun(x) return x;"},
+		{"() => 5", []string{}, "fun() return 5;"},
+		{"(x) => (y) => x + y", []string{"x"}, "fun(x) return fun(y) return (x + y);;"},
+	}
+	for _, tt := range tests {
+		l := tokenizer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkErrors(t, p)
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+				1, len(program.Statements))
+		}
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		function, ok := stmt.Expression.(*ast.FunctionLiteral)
+		if !ok {
+			t.Fatalf("stmt.Expression is not ast.FunctionLiteral. got=%T", stmt.Expression)
+		}
+		if len(function.Parameters) != len(tt.expectedParams) {
+			t.Fatalf("length parameters wrong. want %d, got=%d\n",
+				len(tt.expectedParams), len(function.Parameters))
+		}
+		for i, ident := range tt.expectedParams {
+			testLiteralExpression(t, function.Parameters[i], ident)
+		}
+		if len(function.Body.Statements) != 1 {
+			t.Fatalf("function.Body.Statements has not 1 statements. got=%d\n",
+				len(function.Body.Statements))
+		}
+		if _, ok := function.Body.Statements[0].(*ast.ReturnStatement); !ok {
+			t.Fatalf("function body stmt is not ast.ReturnStatement. got=%T",
+				function.Body.Statements[0])
+		}
+		if program.String() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+}
+
+func TestTypeAnnotationParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let n: int = 0;", "let n: int=0;"},
+		{"const xs: [int] = [1];", "const xs: [int]=[1];"},
+		{"let h: {string: [int]} = {};", "let h: {string: [int]}={};"},
+		{"let s: #{string} = #{};", "let s: #{string}=#{};"},
+		{"let f: fun(int, bool) -> [int] = g;", "let f: fun(int, bool) -> [int]=g;"},
+		{"let f: fun() -> fun(int) -> int = g;", "let f: fun() -> fun(int) -> int=g;"},
+		{"fun(x: int, ys: [int]) -> int { x }", "fun(x: int, ys: [int]) -> int x"},
+		{"fun(x, y: string) { x }", "fun(x, y: string) x"},
+		{"fun add(x: int) -> int { x }", "fun add(x: int) -> int x"},
+		{"(x: int, f: fun(int) -> bool) => f(x)", "fun(x: int, f: fun(int) -> bool) return f(x);"},
+		{"(x: {string: int}) => x", "fun(x: {string: int}) return x;"},
+	}
+	for _, tt := range tests {
+		p := parser.New(tokenizer.New(tt.input))
+		program := p.ParseProgram()
+		checkErrors(t, p)
+		if program.String() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+
+	p := parser.New(tokenizer.New("fun(x: int, y) -> [int] { x }"))
+	program := p.ParseProgram()
+	checkErrors(t, p)
+	function := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	if _, ok := function.Parameters[0].Annotation.(*ast.NamedType); !ok {
+		t.Errorf("x annotation is not *ast.NamedType. got=%T", function.Parameters[0].Annotation)
+	}
+	if function.Parameters[1].Annotation != nil {
+		t.Errorf("y annotation is not nil. got=%T", function.Parameters[1].Annotation)
+	}
+	if arrType, ok := function.ReturnType.(*ast.ArrayType); !ok || arrType.Element.String() != "int" {
+		t.Errorf("return type is not [int]. got=%v", function.ReturnType)
+	}
+}
+
+func TestTypeAnnotationParsingErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let n: = 0;", "expected type annotation, but got ="},
+		{"fun(x: [int) { x }", "expected token []], but got )"},
+		{"fun() -> { 1 }", "expected type annotation, but got INT"},
+		{"let f: fun(int) = g;", "expected token [->], but got ="},
+		{"fun(1) { 1 }", "expected token [IDENTIF], but got INT"},
+	}
+	for _, tt := range tests {
+		p := parser.New(tokenizer.New(tt.input))
+		p.ParseProgram()
+		errors := p.GetErrors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("input=%q, expected error %q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
+func TestStructDefinitionParsing(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedName   string
+		expectedFields []string
+		expected       string
+	}{
+		{"struct Point { x, y }", "Point", []string{"x", "y"}, "struct Point { x, y }"},
+		{"struct Point { x: int, y: int, };", "Point", []string{"x", "y"}, "struct Point { x: int, y: int }"},
+		{"struct Empty {}", "Empty", []string{}, "struct Empty {  }"},
+	}
+	for _, tt := range tests {
+		p := parser.New(tokenizer.New(tt.input))
+		program := p.ParseProgram()
+		checkErrors(t, p)
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statements. got=%d", len(program.Statements))
+		}
+		def, ok := program.Statements[0].(*ast.StructDefinition)
+		if !ok {
+			t.Fatalf("stmt is not *ast.StructDefinition. got=%T", program.Statements[0])
+		}
+		if def.Name.Value != tt.expectedName {
+			t.Errorf("def.Name wrong. expected=%q, got=%q", tt.expectedName, def.Name.Value)
+		}
+		if len(def.Fields) != len(tt.expectedFields) {
+			t.Fatalf("def.Fields wrong length. expected=%d, got=%d", len(tt.expectedFields), len(def.Fields))
+		}
+		for i, field := range tt.expectedFields {
+			if def.Fields[i].Value != field {
+				t.Errorf("field %d wrong. expected=%q, got=%q", i, field, def.Fields[i].Value)
+			}
+		}
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestTypeDefinitionParsing(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedName     string
+		expectedVariants []string
+		expected         string
+	}{
+		{"type Shape = Circle(r) | Rect(w, h)", "Shape", []string{"Circle", "Rect"}, "type Shape = Circle(r) | Rect(w, h)"},
+		{"type Option = Some(value: int) | None;", "Option", []string{"Some", "None"}, "type Option = Some(value: int) | None"},
+		{"type Unit = Unit", "Unit", []string{"Unit"}, "type Unit = Unit"},
+	}
+	for _, tt := range tests {
+		p := parser.New(tokenizer.New(tt.input))
+		program := p.ParseProgram()
+		checkErrors(t, p)
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statements. got=%d", len(program.Statements))
+		}
+		def, ok := program.Statements[0].(*ast.TypeDefinition)
+		if !ok {
+			t.Fatalf("stmt is not *ast.TypeDefinition. got=%T", program.Statements[0])
+		}
+		if def.Name.Value != tt.expectedName {
+			t.Errorf("def.Name wrong. expected=%q, got=%q", tt.expectedName, def.Name.Value)
+		}
+		if len(def.Variants) != len(tt.expectedVariants) {
+			t.Fatalf("def.Variants wrong length. expected=%d, got=%d", len(tt.expectedVariants), len(def.Variants))
+		}
+		for i, variant := range tt.expectedVariants {
+			if def.Variants[i].Name.Value != variant {
+				t.Errorf("variant %d wrong. expected=%q, got=%q", i, variant, def.Variants[i].Name.Value)
+			}
+		}
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestTypeIdentifierParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"type(x)", "type(x)"},
+		{"type = 5;", "type=5;"},
+		{"let type = 5;", "let type=5;"},
+		{"type", "type"},
+	}
+	for _, tt := range tests {
+		p := parser.New(tokenizer.New(tt.input))
+		program := p.ParseProgram()
+		checkErrors(t, p)
+		if program.String() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+}
+
+func TestTryExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedCatch   bool
+		expectedFinally bool
+		expected        string
+	}{
+		{"try { f() } catch (e) { g(e) }", true, false, "try f() catch(e) g(e)"},
+		{"try { f() } finally { g() }", false, true, "try f() finally g()"},
+		{"try { f() } catch (err) { 1 } finally { g() }", true, true, "try f() catch(err) 1 finally g()"},
+	}
+	for _, tt := range tests {
+		p := parser.New(tokenizer.New(tt.input))
+		program := p.ParseProgram()
+		checkErrors(t, p)
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.ExpressionStatement. got=%T", program.Statements[0])
+		}
+		tryExp, ok := stmt.Expression.(*ast.TryExpression)
+		if !ok {
+			t.Fatalf("expression is not *ast.TryExpression. got=%T", stmt.Expression)
+		}
+		if (tryExp.Catch != nil) != tt.expectedCatch || (tryExp.Finally != nil) != tt.expectedFinally {
+			t.Errorf("input=%q, expected catch=%t finally=%t", tt.input, tt.expectedCatch, tt.expectedFinally)
+		}
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestThrowStatementParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`throw "boom";`, "throw boom;"},
+		{`throw {"message": "m"}`, "throw {message : m};"},
+		{"throw 1 + 2", "throw (1 + 2);"},
+	}
+	for _, tt := range tests {
+		p := parser.New(tokenizer.New(tt.input))
+		program := p.ParseProgram()
+		checkErrors(t, p)
+		if _, ok := program.Statements[0].(*ast.ThrowStatement); !ok {
+			t.Fatalf("stmt is not *ast.ThrowStatement. got=%T", program.Statements[0])
+		}
+		if program.String() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+}
+
+func TestFieldAssignmentParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"p.x = 5;", "(p.x)=5;"},
+		{"p.x =& q;", "(p.x)=&q;"},
+		{"p.x =* 1 + 2", "(p.x)=*(1 + 2);"},
+		{"line.start.x = line.end.x + 1", "((line.start).x)=(((line.end).x) + 1);"},
+	}
+	for _, tt := range tests {
+		p := parser.New(tokenizer.New(tt.input))
+		program := p.ParseProgram()
+		checkErrors(t, p)
+		if _, ok := program.Statements[0].(*ast.FieldAssignmentStatement); !ok {
+			t.Fatalf("stmt is not *ast.FieldAssignmentStatement. got=%T", program.Statements[0])
+		}
+		if program.String() != tt.expected {
+			t.Errorf("input=%q, expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+}
+
+func TestStructParsingErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"struct { x }", "expected token [IDENTIF], but got {"},
+		{"struct Point x, y", "expected token [{], but got IDENTIF"},
+		{"struct Point { x y }", "expected token [,], but got IDENTIF"},
+		{"struct Point { x, x }", "duplicate field x in struct Point"},
+		{"p.(1) = 2", "expected field name to assign, but got 1"},
+		{"a?.b = 1", "cannot assign to optional field (a?.b)"},
+		{"try { 1 }", "expected token [CATCH FINALLY], but got EOF"},
+		{"try { 1 } catch { 2 }", "expected token [(], but got {"},
+		{"try { 1 } catch (1) { 2 }", "expected token [IDENTIF], but got INT"},
+		{"try 1", "expected token [{], but got INT"},
+		{"type Shape Circle(r)", "expected token [=], but got IDENTIF"},
+		{"type Shape = | Circle(r)", "expected token [IDENTIF], but got |"},
+		{"type Shape = Circle(r) | Circle(d)", "duplicate variant Circle in type Shape"},
+		{"type Shape = Rect(w, w)", "duplicate field w in variant Rect"},
+	}
+	for _, tt := range tests {
+		p := parser.New(tokenizer.New(tt.input))
+		p.ParseProgram()
+		errors := p.GetErrors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("input=%q, expected error %q, got=%q", tt.input, tt.expected, errors)
+		}
+	}
+}
+
 func TestCallExpressionParsing(t *testing.T) {
 	input := "add(1, 2 * 3, 4 + 5);"
 	l := tokenizer.New(input)
@@ -762,6 +1220,78 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
 		},
+		{
+			"(a)",
+			"a",
+		},
+		{
+			"a[1:2]",
+			"(a[1:2])",
+		},
+		{
+			"a[:b + 1] + c[-1:]",
+			"((a[:(b + 1)]) + (c[(-1):]))",
+		},
+		{
+			"a[:][0]",
+			"((a[:])[0])",
+		},
+		{
+			"a |> f |> g(b)",
+			"((a |> f) |> g(b))",
+		},
+		{
+			"a + b |> f(c) | d",
+			"((a + b) |> (f(c) | d))",
+		},
+		{
+			"(a) + (b)",
+			"(a + b)",
+		},
+		{
+			"sort(a, (x, y) => x < y, (b))",
+			"sort(a, fun(x, y) return (x < y);, b)",
+		},
+		{
+			"a.b + c.d",
+			"((a.b) + (c.d))",
+		},
+		{
+			"a.b.c",
+			"((a.b).c)",
+		},
+		{
+			"a.b(c) * 2",
+			"((a.b)(c) * 2)",
+		},
+		{
+			"-a.b",
+			"(-(a.b))",
+		},
+		{
+			"a?.b?.c",
+			"((a?.b)?.c)",
+		},
+		{
+			"a?.b.c(d)",
+			"((a?.b).c)(d)",
+		},
+		{
+			"a ?? b ?? c",
+			"((a ?? b) ?? c)",
+		},
+		{
+			"a?.b ?? c + d",
+			"((a?.b) ?? (c + d))",
+		},
+		{
+			"a ?? b | c",
+			"(a ?? (b | c))",
+		},
+		{
+			"a ?? b |> f",
+			"((a ?? b) |> f)",
+		},
 	}
 	for _, tt := range tests {
 		l := tokenizer.New(tt.input)
@@ -855,6 +1385,60 @@ func TestLetStatements(t *testing.T) {
 
 }
 
+func TestReassignmentStatements(t *testing.T) {
+	input := `x = 5; y =& x; z =* y;`
+	l := tokenizer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	checkErrors(t, p)
+	if len(program.Statements) != 3 {
+		t.Fatalf("program.Statements does not contain 3 statements. got=%d",
+			len(program.Statements))
+	}
+	for i, name := range []string{"x", "y", "z"} {
+		stmt, ok := program.Statements[i].(*ast.AssignmentStatement)
+		if !ok {
+			t.Fatalf("stmt not *ast.AssignmentStatement. got=%T", program.Statements[i])
+		}
+		if stmt.Token.Type != token.IDENTIF {
+			t.Errorf("stmt.Token.Type not IDENTIF. got=%q", stmt.Token.Type)
+		}
+		if stmt.Name.Value != name {
+			t.Errorf("stmt.Name.Value not '%s'. got=%s", name, stmt.Name.Value)
+		}
+	}
+	if program.String() != "x=5;y=&x;z=*y;" {
+		t.Errorf("program.String() wrong. got=%q", program.String())
+	}
+}
+
+func TestConstStatements(t *testing.T) {
+	input := `const x = 5; const y =& [1];`
+	l := tokenizer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	checkErrors(t, p)
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements does not contain 2 statements. got=%d",
+			len(program.Statements))
+	}
+	for i, name := range []string{"x", "y"} {
+		stmt, ok := program.Statements[i].(*ast.AssignmentStatement)
+		if !ok {
+			t.Fatalf("stmt not *ast.AssignmentStatement. got=%T", program.Statements[i])
+		}
+		if stmt.TokenLiteral() != "const" {
+			t.Errorf("stmt.TokenLiteral not 'const'. got=%q", stmt.TokenLiteral())
+		}
+		if stmt.Name.Value != name {
+			t.Errorf("stmt.Name.Value not '%s'. got=%s", name, stmt.Name.Value)
+		}
+	}
+	if program.String() != "const x=5;const y=&[1];" {
+		t.Errorf("program.String() wrong. got=%q", program.String())
+	}
+}
+
 func checkErrors(t *testing.T, p *parser.Parser) {
 	errors := p.GetErrors()
 	if len(errors) == 0 {
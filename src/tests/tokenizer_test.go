@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/Youssef-Mak/baby-interpreter/pkg/token"
@@ -186,3 +187,309 @@ while(3<45) {
 	}
 
 }
+
+func TestSetTokenizer(t *testing.T) {
+	input := `#{1, 2} # {}`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.SET_LBRACE, "#{"},
+		{token.INT, "1"},
+		{token.COMMA, ","},
+		{token.INT, "2"},
+		{token.RBRACE, "}"},
+		{token.ILLEGAL, "#"},
+		{token.LBRACE, "{"},
+		{token.RBRACE, "}"},
+		{token.EOF, ""},
+	}
+
+	l := tokenizer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestArrowTokenizer(t *testing.T) {
+	input := `(x, y) => x =>= y |> f | g -> -1`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LPAREN, "("},
+		{token.IDENTIF, "x"},
+		{token.COMMA, ","},
+		{token.IDENTIF, "y"},
+		{token.RPAREN, ")"},
+		{token.ARROW, "=>"},
+		{token.IDENTIF, "x"},
+		{token.ARROW, "=>"},
+		{token.ASSIGN, "="},
+		{token.IDENTIF, "y"},
+		{token.PIPE, "|>"},
+		{token.IDENTIF, "f"},
+		{token.OR, "|"},
+		{token.IDENTIF, "g"},
+		{token.THIN_ARROW, "->"},
+		{token.MINUS, "-"},
+		{token.INT, "1"},
+		{token.EOF, ""},
+	}
+
+	l := tokenizer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestColonTokenizer(t *testing.T) {
+	input := `xs[1:]; {"a": 1}`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENTIF, "xs"},
+		{token.LBRACKET, "["},
+		{token.INT, "1"},
+		{token.COLON, ":"},
+		{token.RBRACKET, "]"},
+		{token.SEMICOLON, ";"},
+		{token.LBRACE, "{"},
+		{token.STRING, "a"},
+		{token.COLON, ":"},
+		{token.INT, "1"},
+		{token.RBRACE, "}"},
+		{token.EOF, ""},
+	}
+
+	l := tokenizer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestTemplateTokenizer(t *testing.T) {
+	input := `"plain" "a ${b} c" "${f("}", "${x}")}" "x`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.STRING, "plain"},
+		{token.TEMPLATE, "a ${b} c"},
+		{token.TEMPLATE, `${f("}", "${x}")}`},
+		{token.STRING, "x"},
+		{token.EOF, ""},
+	}
+
+	l := tokenizer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestStringEscapes(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedText    string
+	}{
+		{`"a\nb"`, token.STRING, `a\nb`, "a\nb"},
+		{`"a\tb"`, token.STRING, `a\tb`, "a\tb"},
+		{`"say \"hi\""`, token.STRING, `say \"hi\"`, `say "hi"`},
+		{`"back\\slash"`, token.STRING, `back\\slash`, `back\slash`},
+		{`"\d+"`, token.STRING, `\d+`, `\d+`},
+		{`"${x}\n"`, token.TEMPLATE, `${x}\n`, "${x}\n"},
+	}
+	for _, tt := range tests {
+		tok := tokenizer.New(tt.input).NextToken()
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Errorf("input=%q, expected %s %q, got=%s %q", tt.input, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+		if text := tokenizer.Unescape(tok.Literal); text != tt.expectedText {
+			t.Errorf("input=%q, expected text %q, got=%q", tt.input, tt.expectedText, text)
+		}
+	}
+}
+
+func TestSplitTemplate(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedTexts []string
+		expectedExprs []string
+		expectedOk    bool
+	}{
+		{"a ${b} c", []string{"a ", " c"}, []string{"b"}, true},
+		{"${a}${b}", []string{"", "", ""}, []string{"a", "b"}, true},
+		{`${ {"k": "}"} }!`, []string{"", "!"}, []string{` {"k": "}"} `}, true},
+		{"$ {a} $", []string{"$ {a} $"}, nil, true},
+		{"a ${b", []string{"a "}, nil, false},
+	}
+
+	for _, tt := range tests {
+		texts, exprs, ok := tokenizer.SplitTemplate(tt.input)
+		if ok != tt.expectedOk {
+			t.Fatalf("input=%q ok wrong. expected=%t, got=%t", tt.input, tt.expectedOk, ok)
+		}
+		if fmt.Sprint(texts) != fmt.Sprint(tt.expectedTexts) {
+			t.Errorf("input=%q texts wrong. expected=%q, got=%q", tt.input, tt.expectedTexts, texts)
+		}
+		if fmt.Sprint(exprs) != fmt.Sprint(tt.expectedExprs) {
+			t.Errorf("input=%q exprs wrong. expected=%q, got=%q", tt.input, tt.expectedExprs, exprs)
+		}
+	}
+}
+
+func TestStructTokenizer(t *testing.T) {
+	input := `struct Point { x: int, y }`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.STRUCT, "struct"},
+		{token.IDENTIF, "Point"},
+		{token.LBRACE, "{"},
+		{token.IDENTIF, "x"},
+		{token.COLON, ":"},
+		{token.IDENTIF, "int"},
+		{token.COMMA, ","},
+		{token.IDENTIF, "y"},
+		{token.RBRACE, "}"},
+		{token.EOF, ""},
+	}
+
+	l := tokenizer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestNullSafeTokenizer(t *testing.T) {
+	input := `a?.b ?? c ? d`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENTIF, "a"},
+		{token.OPTIONAL_DOT, "?."},
+		{token.IDENTIF, "b"},
+		{token.COALESCE, "??"},
+		{token.IDENTIF, "c"},
+		{token.ILLEGAL, "?"},
+		{token.IDENTIF, "d"},
+		{token.EOF, ""},
+	}
+
+	l := tokenizer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestExceptionTokenizer(t *testing.T) {
+	input := `try { throw e; } catch (e) {} finally {}`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.TRY, "try"},
+		{token.LBRACE, "{"},
+		{token.THROW, "throw"},
+		{token.IDENTIF, "e"},
+		{token.SEMICOLON, ";"},
+		{token.RBRACE, "}"},
+		{token.CATCH, "catch"},
+		{token.LPAREN, "("},
+		{token.IDENTIF, "e"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.RBRACE, "}"},
+		{token.FINALLY, "finally"},
+		{token.LBRACE, "{"},
+		{token.RBRACE, "}"},
+		{token.EOF, ""},
+	}
+
+	l := tokenizer.New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
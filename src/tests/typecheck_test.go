@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/Youssef-Mak/baby-interpreter/pkg/parser"
+	"github.com/Youssef-Mak/baby-interpreter/pkg/tokenizer"
+	"github.com/Youssef-Mak/baby-interpreter/pkg/typecheck"
+)
+
+func testCheck(t *testing.T, input string) []string {
+	p := parser.New(tokenizer.New(input))
+	program := p.ParseProgram()
+	checkErrors(t, p)
+	return typecheck.Check(program)
+}
+
+func TestTypeCheckValidPrograms(t *testing.T) {
+	tests := []string{
+		"let n: int = 0; n = n + 1;",
+		"let s: string = \"a\" + \"b\";",
+		"let xs: [int] = [1, 2]; let x: int = xs[0];",
+		"let h: {string: [int]} = {\"a\": [1]};",
+		"let members: #{int} = #{1} | #{2};",
+		"let add = fun(x: int, y: int) -> int { x + y }; let z: int = add(1, 2);",
+		"fun twice(f: fun(int) -> int, x: int) -> int { return f(f(x)); }; twice((x) => x + 1, 1);",
+		"let lt: fun(int, int) -> bool = (x: int, y: int) => x < y;",
+		"let double = (x: int) => x * 2; let y: int = double(2);",
+		"let n: int = len([1]) + int(\"2\");",
+		"let s: string = str(1) + \"${1}\" + format(\"%d\", 1);",
+		"let x = 1; x = \"a\"; x + \"b\";",
+		"let x: any = 1; x = \"a\";",
+		"let f = fun(x) { x + 1 }; f(\"a\") + 1;",
+		"let n: int = 1 |> (x: int) => x + 1;",
+		"fun isEven(n: int) -> bool { if (n =*= 0) { return true; }; return isOdd(n - 1); }; fun isOdd(n: int) -> bool { if (n =*= 0) { return false; }; return isEven(n - 1); };",
+		"let xs: [any] = [1, \"a\"];",
+		"let xs: [int] = [];",
+		"let s: string = \"héllo\"[1:];",
+		"struct Point { x: int, y: int }; let p: Point = Point(1, 2); let n: int = p.x + p.y; p.x = 3;",
+		"let p: Point = Point(1, \"a\"); struct Point { x, y: string };",
+		"struct Box { value }; let b = Box(1); b.value = \"a\";",
+		"type Shape = Circle(r: int) | Rect(w: int, h: int) | Empty; let shapes: [Shape] = [Circle(1), Rect(1, 2), Empty];",
+		"type Shape = Circle(r: int) | Square(r: int); let s: Shape = Square(2); let n: int = s.r;",
+		"type Tree = Leaf | Node(left: Tree, value: int, right: Tree); let t: Tree = Node(Leaf, 1, Leaf);",
+		"let s: string = tag(1);",
+		"let h: {string: int} = {\"a\": 1}; let n: int = h.a + h[\"b\"]; h.c = 3;",
+		"let h = {\"a\": 1}; h.b = \"x\"; let s: string = h.b;",
+		"let h: {int: string} = {1: \"a\"}; let s: string = h[1] + h.1;",
+		"let h = {}; h.a = 1; h[2];",
+		"let h: {string: int} = {\"a\": 1}; let n: int = h?.b ?? 0;",
+		"let s: string = {}.a ?? \"a\";",
+		"let cfg = {\"port\": 80}; cfg?.host ?? \"localhost\";",
+		"let h = {\"a\": 1}; h.b ?? \"none\"; [1, 2][5] ?? \"missing\";",
+		"let n: int = try { 1 } catch (e) { e.message };",
+		"try { throw \"a\"; } catch (e) { let s: string = e.type; } finally { 1 };",
+		"let x = 1; let f = fun() { x + \"a\" }; x = \"s\"; f();",
+		"let x = 1; let set = fun() { x = \"s\"; }; set(); x + \"a\";",
+		"struct Point { x: int }; fun f() -> int { struct Point { x: string }; let p: Point = Point(\"a\"); 1 }; let p: Point = Point(1);",
+	}
+	for _, input := range tests {
+		if diagnostics := testCheck(t, input); len(diagnostics) != 0 {
+			t.Errorf("input=%q, expected no diagnostics, got=%q", input, diagnostics)
+		}
+	}
+}
+
+func TestTypeCheckDiagnostics(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"let n: int = \"a\";", []string{"cannot assign string to n: int"}},
+		{"let n: int = 0; n = true;", []string{"cannot assign bool to n: int"}},
+		{"let xs: [int] = [\"a\"];", []string{"cannot assign [string] to xs: [int]"}},
+		{"let h: {string: int} = {1: 1};", []string{"cannot assign {int: int} to h: {string: int}"}},
+		{"let add = fun(x: int, y: int) { x + y }; add(1, \"b\");", []string{"argument 2 of add: cannot use string as int"}},
+		{"let add = fun(x: int, y: int) { x + y }; add(1);", []string{"add expects 2 arguments, got 1"}},
+		{"let inc = fun(x: int) { x + 1 }; \"a\" |> inc;", []string{"argument 1 of inc: cannot use string as int"}},
+		{"fun greet(name: string) -> string { return 5; };", []string{"cannot return int from greet: expected string"}},
+		{"let f = fun() -> int { \"a\" };", []string{"cannot return string from <anonymous>: expected int"}},
+		{"let f = fun(x: int) -> int { if (x > 0) { return x; }; return \"neg\"; };", []string{"cannot return string from <anonymous>: expected int"}},
+		{"let f: fun(int) -> int = (x: string) => x;", []string{"cannot assign fun(string) -> string to f: fun(int) -> int"}},
+		{"1 + \"a\";", []string{"type mismatch: int + string"}},
+		{"\"a\" - \"b\";", []string{"unknown operator: string - string"}},
+		{"-\"a\";", []string{"unknown operator: -string"}},
+		{"let xs: [int] = [1]; xs[\"a\"];", []string{"cannot index [int] with string"}},
+		{"let x = 1; x(2);", []string{"x is not callable: int"}},
+		{"let s: string = len(\"a\");", []string{"cannot assign int to s: string"}},
+		{"let f = fun(x: foo) -> foo { x };", []string{"unknown type: foo"}},
+		{"let f = fun(x: int) { let y: string = x; y };", []string{"cannot assign int to y: string"}},
+		{"let n: int = 1 + \"a\"; n(1);", []string{"type mismatch: int + string", "n is not callable: int"}},
+		{"struct Point { x: int, y: int }; Point(1, \"a\");", []string{"argument 2 of Point: cannot use string as int"}},
+		{"struct Point { x: int, y: int }; Point(1);", []string{"Point expects 2 arguments, got 1"}},
+		{"struct Point { x: int, y: int }; let p = Point(1, 2); p.z;", []string{"Point has no field z"}},
+		{"struct Point { x: int, y: int }; let p = Point(1, 2); p.x = \"a\";", []string{"cannot assign string to p.x: int"}},
+		{"struct Point { x: int, y: int }; let s: string = Point(1, 2).x;", []string{"cannot assign int to s: string"}},
+		{"struct Point { x: int }; let n: int = Point(1);", []string{"cannot assign Point to n: int"}},
+		{"struct Line { start: Vec }", []string{"unknown type: Vec"}},
+		{"fun f() { struct Local { x: int }; Local(1) }; let l: Local = f();", []string{"unknown type: Local"}},
+		{"if (true) { type Shape = Circle(r: int) | Empty; }; let s: Shape = 1;", []string{"unknown type: Shape"}},
+		{"let h: {string: int} = {\"a\": 1}; let s: string = h.a;", []string{"cannot assign int to s: string"}},
+		{"let h: {string: int} = {\"a\": 1}; h.b = \"x\";", []string{"cannot assign string to h.b: int"}},
+		{"let h: {int: int} = {1: 1}; h.a;", []string{"cannot index {int: int} with string"}},
+		{"let h: {string: int} = {\"a\": 1}; h[1];", []string{"cannot index {string: int} with int"}},
+		{"let h: {string: int} = {\"a\": 1}; let s: string = h?.a ?? 0;", []string{"cannot assign int to s: string"}},
+		{"throw 1 + \"a\";", []string{"type mismatch: int + string"}},
+		{"try { 1 + \"a\"; } catch (e) { -\"b\"; } finally { let n: int = \"c\"; };", []string{"type mismatch: int + string", "unknown operator: -string", "cannot assign string to n: int"}},
+		{"type Shape = Circle(r: int) | Empty; Circle(\"a\");", []string{"argument 1 of Circle: cannot use string as int"}},
+		{"type Shape = Circle(r: int) | Empty; let n: int = Empty;", []string{"cannot assign Shape to n: int"}},
+		{"type Shape = Circle(r: int) | Empty; let s: Shape = Circle(1); s.d;", []string{"Shape has no field d"}},
+		{"type Shape = Circle(r: int) | Empty; let s: Shape = 1;", []string{"cannot assign int to s: Shape"}},
+		{"type Tree = Leaf | Node(left: Tree, right: Tree); Node(Leaf, 1);", []string{"argument 2 of Node: cannot use int as Tree"}},
+	}
+	for _, tt := range tests {
+		diagnostics := testCheck(t, tt.input)
+		if len(diagnostics) != len(tt.expected) {
+			t.Errorf("input=%q, expected diagnostics %q, got=%q", tt.input, tt.expected, diagnostics)
+			continue
+		}
+		for i, expected := range tt.expected {
+			if diagnostics[i] != expected {
+				t.Errorf("input=%q, expected diagnostic %q, got=%q", tt.input, expected, diagnostics[i])
+			}
+		}
+	}
+}
+
+func TestTypeCheckSession(t *testing.T) {
+	session := typecheck.NewSession()
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"struct Point { x: int }; let n: int = 1;", 0},
+		{"let p: Point = Point(n);", 0},
+		{"let s: string = \"a\"; let m: int = s;", 1},
+		{"let t: int = p.x + n; let u: int = s;", 0},
+	}
+	for _, tt := range tests {
+		p := parser.New(tokenizer.New(tt.input))
+		program := p.ParseProgram()
+		checkErrors(t, p)
+		if diagnostics := session.Check(program); len(diagnostics) != tt.expected {
+			t.Errorf("input=%q, expected %d diagnostics, got=%q", tt.input, tt.expected, diagnostics)
+		}
+	}
+}
+
+func TestAnnotationsIgnoredByEval(t *testing.T) {
+	input := "let add = fun(x: int, y: int) -> int { x + y }; let n: int = add(1, 2); n"
+	testIntegerObject(t, testEval(input), 3)
+}